@@ -0,0 +1,229 @@
+// Package batch runs resumable jobs over a dataset on top of gopager.
+//
+// A Runner fetches the dataset page by page with a
+// *gopager.CursorPager[*gopager.DefaultCursor] and passes every page to a
+// user function. After each processed page the cursor of the next page is
+// saved to a CheckpointStore, so a restarted job resumes where it stopped.
+// Pages are processed at least once: a page whose processing was interrupted
+// is processed again on restart.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Alp4ka/gopager"
+)
+
+const (
+	_defaultRetryAttempts = 3
+	_defaultRetryBackoff  = 100 * time.Millisecond
+	_defaultMaxBackoff    = 10 * time.Second
+)
+
+// ProcessFunc processes a single page of the dataset. A returned error stops
+// the job, the page is processed again on restart.
+type ProcessFunc[T any] func(ctx context.Context, page []T) error
+
+// Runner processes a dataset page by page and checkpoints its progress.
+type Runner[T any] struct {
+	job     string
+	store   CheckpointStore
+	pager   *gopager.CursorPager[*gopager.DefaultCursor]
+	getters gopager.Getters[T]
+	// attempts is the maximum number of attempts of a database operation.
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	retryable  func(error) bool
+}
+
+// NewRunner returns a runner of the job. The progress is saved to the store
+// under the job name. The pager defines the page size, the orderings and the
+// codec of checkpoint tokens, its cursor is the start of a job without a
+// checkpoint.
+//
+// IMPORTANT:
+// Do not set a token TTL on the pager: an expired checkpoint cannot be resumed.
+func NewRunner[T any](
+	job string,
+	store CheckpointStore,
+	pager *gopager.CursorPager[*gopager.DefaultCursor],
+	getters gopager.Getters[T],
+) *Runner[T] {
+	return &Runner[T]{
+		job:        job,
+		store:      store,
+		pager:      pager,
+		getters:    getters,
+		attempts:   _defaultRetryAttempts,
+		backoff:    _defaultRetryBackoff,
+		maxBackoff: _defaultMaxBackoff,
+		retryable:  isTransient,
+	}
+}
+
+// WithRetry sets the maximum number of attempts of fetching a page and of
+// saving a checkpoint. The delay before the next attempt starts at backoff
+// and doubles up to maxBackoff. One attempt disables retries.
+func (r *Runner[T]) WithRetry(attempts int, backoff time.Duration, maxBackoff time.Duration) *Runner[T] {
+	if r == nil {
+		r = new(Runner[T])
+	}
+
+	r.attempts = attempts
+	r.backoff = backoff
+	r.maxBackoff = maxBackoff
+
+	return r
+}
+
+// WithRetryIf sets the function reporting whether an error is transient and
+// the operation can be retried. By default, errors classified by
+// gopager.ErrorCode as gopager.CodeUnknown (e.g. database errors) are retried.
+func (r *Runner[T]) WithRetryIf(retryable func(error) bool) *Runner[T] {
+	if r == nil {
+		r = new(Runner[T])
+	}
+
+	r.retryable = retryable
+
+	return r
+}
+
+// Run processes the dataset queried by db starting from the last checkpoint
+// of the job. The checkpoint is removed when the last page is processed, so
+// the next run starts over.
+func (r *Runner[T]) Run(ctx context.Context, db *gorm.DB, process ProcessFunc[T]) error {
+	if r == nil || r.store == nil || r.job == "" {
+		return fmt.Errorf("batch runner requires a job name and a checkpoint store")
+	}
+
+	pager, err := r.resume(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot resume job '%s': %w", r.job, err)
+	}
+
+	for {
+		var (
+			page []T
+			next *gopager.DefaultCursor
+		)
+		err = r.retry(ctx, func() error {
+			page, next, err = r.fetch(ctx, db, pager)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("job '%s': %w", r.job, err)
+		}
+
+		if len(page) != 0 {
+			err = process(ctx, page)
+			if err != nil {
+				return fmt.Errorf("job '%s': cannot process page: %w", r.job, err)
+			}
+		}
+
+		if next == nil {
+			err = r.retry(ctx, func() error { return r.store.Delete(ctx, r.job) })
+			if err != nil {
+				return fmt.Errorf("job '%s': cannot delete checkpoint: %w", r.job, err)
+			}
+
+			return nil
+		}
+
+		err = r.checkpoint(ctx, next)
+		if err != nil {
+			return fmt.Errorf("job '%s': cannot save checkpoint: %w", r.job, err)
+		}
+
+		pager = pager.WithCursor(next)
+	}
+}
+
+// resume returns a copy of the pager starting from the last checkpoint.
+func (r *Runner[T]) resume(ctx context.Context) (*gopager.CursorPager[*gopager.DefaultCursor], error) {
+	ret := gopager.NewCursorPager[*gopager.DefaultCursor]()
+	if r.pager != nil {
+		*ret = *r.pager
+	}
+
+	var token string
+	err := r.retry(ctx, func() error {
+		var err error
+		token, err = r.store.Load(ctx, r.job)
+		return err
+	})
+	if err != nil || token == "" {
+		return ret, err
+	}
+
+	cursor, err := ret.GetTokenCodec().DecodeCursor(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+
+	return ret.WithCursor(cursor), nil
+}
+
+// fetch fetches the page of the pager and builds the cursor of the next page.
+func (r *Runner[T]) fetch(
+	ctx context.Context,
+	db *gorm.DB,
+	pager *gopager.CursorPager[*gopager.DefaultCursor],
+) ([]T, *gopager.DefaultCursor, error) {
+	paged, err := pager.Paginate(db.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resultSet []T
+	err = paged.Find(&resultSet).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot fetch page: %w", err)
+	}
+
+	return gopager.NextPageCursor(pager, resultSet, r.getters)
+}
+
+// checkpoint saves the cursor of the next page.
+func (r *Runner[T]) checkpoint(ctx context.Context, next *gopager.DefaultCursor) error {
+	token, err := next.Encode()
+	if err != nil {
+		return err
+	}
+
+	return r.retry(ctx, func() error { return r.store.Save(ctx, r.job, token) })
+}
+
+// retry runs op until it succeeds, fails with a permanent error or runs out
+// of attempts.
+func (r *Runner[T]) retry(ctx context.Context, op func() error) error {
+	backoff := r.backoff
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= r.attempts || r.retryable == nil || !r.retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w, last error: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		backoff = min(2*backoff, r.maxBackoff)
+	}
+}
+
+// isTransient reports whether the error is not caused by the pager setup,
+// the request or the context.
+func isTransient(err error) bool {
+	return gopager.ErrorCode(err) == gopager.CodeUnknown
+}
@@ -0,0 +1,200 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/Alp4ka/gopager"
+)
+
+type row struct {
+	ID int
+}
+
+var _getters = gopager.Getters[row]{
+	"id": func(r row) any { return r.ID },
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// Every connection opens its own in-memory database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&row{}))
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, db.Create(&row{ID: i}).Error)
+	}
+
+	return db
+}
+
+func newPager() *gopager.CursorPager[*gopager.DefaultCursor] {
+	return gopager.NewCursorPager[*gopager.DefaultCursor]().WithLimit(3).WithLookahead().
+		WithSort(gopager.OrderBy{Column: "id", Direction: gopager.DirectionASC})
+}
+
+func ids(rows []row) []int {
+	ret := make([]int, 0, len(rows))
+	for _, r := range rows {
+		ret = append(ret, r.ID)
+	}
+	return ret
+}
+
+func Test_Runner_Run(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	store := NewMemoryStore()
+
+	var got [][]int
+	err := NewRunner("export", store, newPager(), _getters).Run(ctx, db.Model(&row{}), func(_ context.Context, page []row) error {
+		got = append(got, ids(page))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {10}}, got)
+
+	token, err := store.Load(ctx, "export")
+	require.NoError(t, err)
+	require.Empty(t, token)
+}
+
+func Test_Runner_Resume(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	store := NewMemoryStore()
+	runner := NewRunner("export", store, newPager(), _getters)
+	errCrash := errors.New("crash")
+
+	var got [][]int
+	err := runner.Run(ctx, db.Model(&row{}), func(_ context.Context, page []row) error {
+		if page[0].ID == 7 {
+			return errCrash
+		}
+		got = append(got, ids(page))
+		return nil
+	})
+	require.ErrorIs(t, err, errCrash)
+	require.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}}, got)
+
+	token, err := store.Load(ctx, "export")
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	// The failed page is processed again.
+	got = nil
+	err = runner.Run(ctx, db.Model(&row{}), func(_ context.Context, page []row) error {
+		got = append(got, ids(page))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]int{{7, 8, 9}, {10}}, got)
+
+	t.Run("invalid checkpoint", func(t *testing.T) {
+		require.NoError(t, store.Save(ctx, "broken", "not a token!"))
+		err := NewRunner("broken", store, newPager(), _getters).Run(ctx, db.Model(&row{}), func(context.Context, []row) error {
+			return nil
+		})
+		require.ErrorIs(t, err, gopager.ErrInvalidToken)
+	})
+}
+
+func Test_Runner_Retry(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	errTransient := errors.New("connection reset by peer")
+
+	// failing fails the given number of queries.
+	var failures int
+	failing := db.Session(&gorm.Session{NewDB: true})
+	require.NoError(t, failing.Callback().Query().Before("gorm:query").Register("test:fail", func(tx *gorm.DB) {
+		if failures > 0 {
+			failures--
+			_ = tx.AddError(errTransient)
+		}
+	}))
+	t.Cleanup(func() { _ = failing.Callback().Query().Remove("test:fail") })
+
+	var pages int
+	process := func(context.Context, []row) error {
+		pages++
+		return nil
+	}
+
+	failures = 2
+	err := NewRunner("retry", NewMemoryStore(), newPager(), _getters).
+		WithRetry(3, time.Millisecond, 2*time.Millisecond).
+		Run(ctx, failing.Model(&row{}), process)
+	require.NoError(t, err)
+	require.Equal(t, 4, pages)
+
+	failures, pages = 3, 0
+	err = NewRunner("retry", NewMemoryStore(), newPager(), _getters).
+		WithRetry(3, time.Millisecond, 2*time.Millisecond).
+		Run(ctx, failing.Model(&row{}), process)
+	require.ErrorIs(t, err, errTransient)
+	require.Zero(t, pages)
+
+	// Errors of the pager setup are not retried.
+	var checks int
+	failures = 0
+	err = NewRunner("retry", NewMemoryStore(), newPager(), gopager.Getters[row]{}).
+		WithRetryIf(func(err error) bool {
+			checks++
+			return isTransient(err)
+		}).
+		Run(ctx, failing.Model(&row{}), process)
+	require.ErrorIs(t, err, gopager.ErrMissingGetter)
+	require.Equal(t, 1, checks)
+}
+
+func Test_CheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	gormStore := NewGORMStore(db).WithTable("checkpoints")
+	require.NoError(t, gormStore.Migrate(ctx))
+
+	stores := map[string]CheckpointStore{
+		"memory": NewMemoryStore(),
+		"file":   NewFileStore(t.TempDir() + "/checkpoints"),
+		"gorm":   gormStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			job := "users/../export"
+
+			token, err := store.Load(ctx, job)
+			require.NoError(t, err)
+			require.Empty(t, token)
+
+			require.NoError(t, store.Save(ctx, job, "first"))
+			require.NoError(t, store.Save(ctx, job, "second"))
+			require.NoError(t, store.Save(ctx, "other", "other"))
+
+			token, err = store.Load(ctx, job)
+			require.NoError(t, err)
+			require.Equal(t, "second", token)
+
+			require.NoError(t, store.Delete(ctx, job))
+			require.NoError(t, store.Delete(ctx, job))
+
+			token, err = store.Load(ctx, job)
+			require.NoError(t, err)
+			require.Empty(t, token)
+
+			token, err = store.Load(ctx, "other")
+			require.NoError(t, err)
+			require.Equal(t, "other", token)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CheckpointStore saves the progress of jobs. Implementations must be safe for
+// concurrent use.
+type CheckpointStore interface {
+	// Load returns the token saved for the job. Returns an empty token if the
+	// job has no checkpoint.
+	Load(ctx context.Context, job string) (string, error)
+	// Save replaces the checkpoint of the job with the token.
+	Save(ctx context.Context, job string, token string) error
+	// Delete removes the checkpoint of the job. Deleting a missing checkpoint
+	// is not an error.
+	Delete(ctx context.Context, job string) error
+}
+
+// MemoryStore keeps checkpoints in memory. Use it in tests or for jobs which
+// resume only within the process, e.g. after a failed page.
+type MemoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tokens: make(map[string]string)}
+}
+
+// Load - implements CheckpointStore.
+func (s *MemoryStore) Load(_ context.Context, job string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tokens[job], nil
+}
+
+// Save - implements CheckpointStore.
+func (s *MemoryStore) Save(_ context.Context, job string, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[job] = token
+
+	return nil
+}
+
+// Delete - implements CheckpointStore.
+func (s *MemoryStore) Delete(_ context.Context, job string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, job)
+
+	return nil
+}
+
+// FileStore keeps the checkpoint of every job in a file of the directory.
+// Files are replaced atomically, so a crash never leaves a partial checkpoint.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a store keeping checkpoints in the directory. The
+// directory is created on the first save.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Load - implements CheckpointStore.
+func (s *FileStore) Load(_ context.Context, job string) (string, error) {
+	data, err := os.ReadFile(s.path(job))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// Save - implements CheckpointStore.
+func (s *FileStore) Save(_ context.Context, job string, token string) error {
+	err := os.MkdirAll(s.dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(s.dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(token)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path(job))
+}
+
+// Delete - implements CheckpointStore.
+func (s *FileStore) Delete(_ context.Context, job string) error {
+	err := os.Remove(s.path(job))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// path returns the checkpoint file of the job. The name is escaped, so any
+// job name stays within the directory.
+func (s *FileStore) path(job string) string {
+	return filepath.Join(s.dir, url.PathEscape(job)+".checkpoint")
+}
+
+// _defaultCheckpointTable is the table of GORMStore unless set with WithTable.
+const _defaultCheckpointTable = "gopager_checkpoints"
+
+// Checkpoint is a row of the GORMStore table.
+type Checkpoint struct {
+	Job       string `gorm:"primaryKey;size:255"`
+	Token     string
+	UpdatedAt time.Time
+}
+
+// GORMStore keeps checkpoints in a database table, one row per job. Create
+// the table with Migrate.
+type GORMStore struct {
+	db    *gorm.DB
+	table string
+}
+
+// NewGORMStore returns a store keeping checkpoints in the gopager_checkpoints
+// table of the database.
+func NewGORMStore(db *gorm.DB) *GORMStore {
+	return &GORMStore{db: db, table: _defaultCheckpointTable}
+}
+
+// WithTable sets the name of the checkpoint table.
+func (s *GORMStore) WithTable(table string) *GORMStore {
+	s.table = table
+	return s
+}
+
+// Migrate creates the checkpoint table if it does not exist.
+func (s *GORMStore) Migrate(ctx context.Context) error {
+	err := s.query(ctx).AutoMigrate(&Checkpoint{})
+	if err != nil {
+		return fmt.Errorf("cannot migrate checkpoint table: %w", err)
+	}
+
+	return nil
+}
+
+// Load - implements CheckpointStore.
+func (s *GORMStore) Load(ctx context.Context, job string) (string, error) {
+	var rows []Checkpoint
+	err := s.query(ctx).Where("job = ?", job).Limit(1).Find(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return "", err
+	}
+
+	return rows[0].Token, nil
+}
+
+// Save - implements CheckpointStore.
+func (s *GORMStore) Save(ctx context.Context, job string, token string) error {
+	return s.query(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "job"}},
+			DoUpdates: clause.AssignmentColumns([]string{"token", "updated_at"}),
+		}).
+		Create(&Checkpoint{Job: job, Token: token}).Error
+}
+
+// Delete - implements CheckpointStore.
+func (s *GORMStore) Delete(ctx context.Context, job string) error {
+	return s.query(ctx).Where("job = ?", job).Delete(&Checkpoint{}).Error
+}
+
+func (s *GORMStore) query(ctx context.Context) *gorm.DB {
+	return s.db.WithContext(ctx).Table(s.table)
+}
+
+var (
+	_ CheckpointStore = (*MemoryStore)(nil)
+	_ CheckpointStore = (*FileStore)(nil)
+	_ CheckpointStore = (*GORMStore)(nil)
+)
@@ -0,0 +1,442 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm/schema"
+)
+
+const (
+	_tagName         = "gopager"
+	_tagSortable     = "sortable"
+	_tagAliasOption  = "alias="
+	_gormTagName     = "gorm"
+	_gormColumnKey   = "COLUMN"
+	_generatedSuffix = "_gopager.go"
+	_tableNameMethod = "TableName"
+)
+
+// config controls what is generated.
+type config struct {
+	// dir is the directory of the package to parse.
+	dir string
+	// types are the names of the structs to generate for. All structs with
+	// sortable fields are used if empty.
+	types []string
+	// qualify prefixes column names with the table name of the struct.
+	qualify bool
+	// tables are the table names used by qualify by struct name. They
+	// override TableName methods and the default naming strategy.
+	tables map[string]string
+}
+
+// sortField is a sortable field of a struct.
+type sortField struct {
+	// Name is the Go name of the field.
+	Name string
+	// Alias is the external sort alias.
+	Alias string
+	// Column is the column name used in orderings.
+	Column string
+}
+
+// model is a struct with sortable fields.
+type model struct {
+	Name   string
+	Fields []sortField
+}
+
+// generate parses the package in cfg.dir and returns the formatted source of
+// the generated file.
+func generate(cfg config) ([]byte, error) {
+	pkgName, files, err := parsePackage(cfg.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	structs := collectStructs(files)
+	tableNames := collectTableNames(files)
+	names := cfg.types
+	if len(names) == 0 {
+		for name := range structs {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+	}
+
+	models := make([]model, 0, len(names))
+	for _, name := range names {
+		st, ok := structs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct '%s' not found", name)
+		}
+
+		fields, err := sortFields(structs, st)
+		if err != nil {
+			return nil, fmt.Errorf("struct '%s': %w", name, err)
+		}
+		if len(fields) == 0 {
+			if len(cfg.types) != 0 {
+				return nil, fmt.Errorf("struct '%s' has no sortable fields", name)
+			}
+			continue
+		}
+
+		if cfg.qualify {
+			table, err := tableName(cfg, tableNames, name)
+			if err != nil {
+				return nil, err
+			}
+			for i := range fields {
+				fields[i].Column = table + "." + fields[i].Column
+			}
+		}
+
+		models = append(models, model{Name: name, Fields: fields})
+	}
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no structs with sortable fields found")
+	}
+
+	var buf bytes.Buffer
+	err = _fileTemplate.Execute(&buf, struct {
+		Package string
+		Models  []model
+	}{pkgName, models})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// outputPath returns the default path of the generated file.
+func outputPath(dir string) string {
+	return filepath.Join(dir, "sortable"+_generatedSuffix)
+}
+
+// parsePackage parses the non-test Go files of the package in dir, skipping
+// previously generated files.
+func parsePackage(dir string) (string, []*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot read package directory: %w", err)
+	}
+
+	var (
+		pkgName string
+		files   []*ast.File
+	)
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") || strings.HasSuffix(name, _generatedSuffix) {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot parse package: %w", err)
+		}
+		if pkgName != "" && file.Name.Name != pkgName {
+			return "", nil, fmt.Errorf("found packages '%s' and '%s' in '%s'", pkgName, file.Name.Name, dir)
+		}
+
+		pkgName = file.Name.Name
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return "", nil, fmt.Errorf("no Go files found in '%s'", dir)
+	}
+
+	return pkgName, files, nil
+}
+
+// collectStructs returns the struct types declared in the files by name.
+func collectStructs(files []*ast.File) map[string]*ast.StructType {
+	ret := make(map[string]*ast.StructType)
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok || spec.TypeParams != nil {
+				return true
+			}
+
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				ret[spec.Name.Name] = st
+			}
+
+			return true
+		})
+	}
+
+	return ret
+}
+
+// collectTableNames returns the table names returned by the TableName
+// methods declared in the files by struct name. A method that does not return
+// a string literal maps to an empty name.
+func collectTableNames(files []*ast.File) map[string]string {
+	ret := make(map[string]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Name.Name != _tableNameMethod {
+				continue
+			}
+
+			recv := fn.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				ret[ident.Name] = returnedString(fn.Body)
+			}
+		}
+	}
+
+	return ret
+}
+
+// returnedString returns the string literal returned by a function body
+// consisting of a single return statement, or an empty string.
+func returnedString(body *ast.BlockStmt) string {
+	if body == nil || len(body.List) != 1 {
+		return ""
+	}
+
+	stmt, ok := body.List[0].(*ast.ReturnStmt)
+	if !ok || len(stmt.Results) != 1 {
+		return ""
+	}
+
+	lit, ok := stmt.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+
+	ret, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+
+	return ret
+}
+
+// tableName returns the table name of a struct: the one set in the config,
+// the one returned by its TableName method or the one derived with the
+// default GORM naming strategy.
+func tableName(cfg config, tableNames map[string]string, name string) (string, error) {
+	if table, ok := cfg.tables[name]; ok {
+		return table, nil
+	}
+
+	table, ok := tableNames[name]
+	switch {
+	case !ok:
+		return schema.NamingStrategy{}.TableName(name), nil
+	case table == "":
+		return "", fmt.Errorf("struct '%s': %s does not return a string literal, set the table name with -table", name, _tableNameMethod)
+	default:
+		return table, nil
+	}
+}
+
+// sortFields returns the sortable fields of a struct, including fields of
+// embedded structs declared in the same package. Embedded pointers are
+// skipped: promoted fields of a nil pointer cannot be read.
+func sortFields(structs map[string]*ast.StructType, st *ast.StructType) ([]sortField, error) {
+	var ret []sortField
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			unquoted, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tag %s: %w", field.Tag.Value, err)
+			}
+			tag = reflect.StructTag(unquoted)
+		}
+
+		if len(field.Names) == 0 {
+			ident, ok := field.Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			embedded, ok := structs[ident.Name]
+			if !ok {
+				continue
+			}
+
+			fields, err := sortFields(structs, embedded)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, fields...)
+			continue
+		}
+
+		sortable, alias, err := parseTag(tag.Get(_tagName))
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", field.Names[0].Name, err)
+		}
+		if !sortable {
+			continue
+		}
+
+		for _, name := range field.Names {
+			column := gormColumn(tag.Get(_gormTagName))
+			if column == "" {
+				column = schema.NamingStrategy{}.ColumnName("", name.Name)
+			}
+
+			field := sortField{Name: name.Name, Alias: alias, Column: column}
+			if field.Alias == "" {
+				field.Alias = column
+			}
+			ret = append(ret, field)
+		}
+	}
+
+	// Aliases must also differ as identifiers of the generated constants.
+	aliases := make(map[string]string, len(ret))
+	columns := make(map[string]string, len(ret))
+	for _, field := range ret {
+		alias := exportedName(field.Alias)
+		if prev, ok := aliases[alias]; ok {
+			return nil, fmt.Errorf("fields '%s' and '%s' have clashing sort aliases", prev, field.Name)
+		}
+		if prev, ok := columns[field.Column]; ok {
+			return nil, fmt.Errorf("fields '%s' and '%s' are mapped to column '%s'", prev, field.Name, field.Column)
+		}
+		aliases[alias], columns[field.Column] = field.Name, field.Name
+	}
+
+	return ret, nil
+}
+
+// parseTag parses a gopager struct tag of the form "sortable,alias=<alias>".
+func parseTag(tag string) (bool, string, error) {
+	if tag == "" {
+		return false, "", nil
+	}
+
+	var (
+		sortable bool
+		alias    string
+	)
+	for _, option := range strings.Split(tag, ",") {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == _tagSortable:
+			sortable = true
+		case strings.HasPrefix(option, _tagAliasOption):
+			alias = strings.TrimPrefix(option, _tagAliasOption)
+			if alias == "" {
+				return false, "", fmt.Errorf("empty sort alias")
+			}
+		default:
+			return false, "", fmt.Errorf("unknown %s tag option '%s'", _tagName, option)
+		}
+	}
+	if alias != "" && !sortable {
+		return false, "", fmt.Errorf("sort alias set for a field that is not sortable")
+	}
+
+	return sortable, alias, nil
+}
+
+// gormColumn returns the value of the column setting of a gorm struct tag.
+func gormColumn(tag string) string {
+	return schema.ParseTagSetting(tag, ";")[_gormColumnKey]
+}
+
+// exportedName converts a sort alias into a part of a Go identifier. Words of
+// the alias are capitalized, common initialisms are upper-cased, e.g.
+// "user_id" becomes "UserID".
+func exportedName(alias string) string {
+	words := strings.FieldsFunc(alias, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); _commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(word)
+		b.WriteRune(unicode.ToUpper(r))
+		b.WriteString(word[size:])
+	}
+
+	return b.String()
+}
+
+// _commonInitialisms are upper-cased in generated names, as golint does.
+var _commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "LHS": true, "QPS": true, "RAM": true, "RHS": true,
+	"RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XMPP": true,
+	"XSRF": true, "XSS": true,
+}
+
+var _fileTemplate = template.Must(template.New("file").Funcs(template.FuncMap{
+	"exported": exportedName,
+}).Parse(`// Code generated by gopager-gen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "github.com/Alp4ka/gopager"
+{{ range .Models }}{{ $model := .Name }}
+// {{ $model }}Getters are the getters of the sortable columns of {{ $model }}.
+var {{ $model }}Getters = gopager.Getters[{{ $model }}]{
+{{- range .Fields }}
+	{{ printf "%q" .Column }}: func(v {{ $model }}) any { return v.{{ .Name }} },
+{{- end }}
+}
+
+// {{ $model }}ColumnMapping maps the sort aliases of {{ $model }} to its columns.
+var {{ $model }}ColumnMapping = gopager.ColumnMapping{
+{{- range .Fields }}
+	{{ $model }}SortAlias{{ exported .Alias }}: {{ printf "%q" .Column }},
+{{- end }}
+}
+
+// Sort aliases of {{ $model }}.
+const (
+{{- range .Fields }}
+	{{ $model }}SortAlias{{ exported .Alias }} = {{ printf "%q" .Alias }}
+{{- end }}
+)
+
+// {{ $model }}SortAliases lists the sort aliases of {{ $model }}.
+var {{ $model }}SortAliases = []string{
+{{- range .Fields }}
+	{{ $model }}SortAlias{{ exported .Alias }},
+{{- end }}
+}
+{{ end }}`))
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var _update = flag.Bool("update", false, "update golden files")
+
+func Test_generate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config
+	}{
+		{"basic", config{}},
+		{"embedded", config{types: []string{"Order", "Item"}}},
+		{"qualified", config{qualify: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join("testdata", tt.name)
+			tt.cfg.dir = dir
+
+			src, err := generate(tt.cfg)
+			require.NoError(t, err)
+
+			golden := filepath.Join(dir, "expected.golden")
+			if *_update {
+				require.NoError(t, os.WriteFile(golden, src, 0o644))
+			}
+
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(src))
+		})
+	}
+}
+
+func Test_generate_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		cfg   config
+	}{
+		{
+			name:  "unknown struct",
+			files: map[string]string{"a.go": "package a\n\ntype A struct {\n\tID int `gopager:\"sortable\"`\n}\n"},
+			cfg:   config{types: []string{"B"}},
+		},
+		{
+			name:  "no sortable fields",
+			files: map[string]string{"a.go": "package a\n\ntype A struct {\n\tID int\n}\n"},
+		},
+		{
+			name:  "unknown tag option",
+			files: map[string]string{"a.go": "package a\n\ntype A struct {\n\tID int `gopager:\"sortabel\"`\n}\n"},
+		},
+		{
+			name:  "alias without sortable",
+			files: map[string]string{"a.go": "package a\n\ntype A struct {\n\tID int `gopager:\"alias=id\"`\n}\n"},
+		},
+		{
+			name:  "clashing aliases",
+			files: map[string]string{"a.go": "package a\n\ntype A struct {\n\tA int `gopager:\"sortable,alias=created_at\"`\n\tB int `gopager:\"sortable,alias=createdAt\"`\n}\n"},
+		},
+		{
+			name:  "shared column",
+			files: map[string]string{"a.go": "package a\n\ntype A struct {\n\tA int `gorm:\"column:x\" gopager:\"sortable,alias=a\"`\n\tB int `gorm:\"column:x\" gopager:\"sortable,alias=b\"`\n}\n"},
+		},
+		{
+			name: "computed table name",
+			files: map[string]string{
+				"a.go": "package a\n\ntype A struct {\n\tID int `gopager:\"sortable\"`\n}\n\nfunc (A) TableName() string {\n\treturn prefix + \"a\"\n}\n",
+			},
+			cfg: config{qualify: true},
+		},
+		{
+			name: "several packages",
+			files: map[string]string{
+				"a.go": "package a\n\ntype A struct {\n\tID int `gopager:\"sortable\"`\n}\n",
+				"b.go": "package b\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+			}
+			tt.cfg.dir = dir
+
+			_, err := generate(tt.cfg)
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_generate_Tables(t *testing.T) {
+	dir := filepath.Join("testdata", "qualified")
+	src, err := generate(config{dir: dir, qualify: true, types: []string{"Transfer"}, tables: map[string]string{"Transfer": "archive.transfers"}})
+	require.NoError(t, err)
+	require.Contains(t, string(src), `"archive.transfers.id":`)
+
+	tables, err := parseTables("User=app_users, Order=orders")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"User": "app_users", "Order": "orders"}, tables)
+
+	_, err = parseTables("User")
+	require.Error(t, err)
+}
+
+func Test_exportedName(t *testing.T) {
+	for alias, expected := range map[string]string{
+		"id":         "ID",
+		"user_id":    "UserID",
+		"users.id":   "UsersID",
+		"avatar_url": "AvatarURL",
+		"api_key":    "APIKey",
+		"createdAt":  "CreatedAt",
+		"identity":   "Identity",
+	} {
+		require.Equal(t, expected, exportedName(alias), alias)
+	}
+}
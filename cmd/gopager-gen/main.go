@@ -0,0 +1,96 @@
+// Command gopager-gen generates typed gopager.Getters, gopager.ColumnMapping
+// and the list of allowed sort aliases for structs with sortable fields.
+//
+// Fields are marked with the gopager struct tag:
+//
+//	type User struct {
+//	    ID        int64     `gopager:"sortable"`
+//	    CreatedAt time.Time `gopager:"sortable,alias=created"`
+//	    Name      string    `gorm:"column:full_name" gopager:"sortable,alias=name"`
+//	}
+//
+// The column name is taken from the gorm column setting or derived with the
+// default GORM naming strategy. With -qualify the table name is taken from
+// -table, from a TableName method returning a string literal or derived with
+// the default GORM naming strategy. The alias defaults to the column name. Fields
+// of embedded structs declared in the same package are included.
+//
+// The package is parsed without loading dependencies, so the generator runs
+// offline. Usage:
+//
+//	//go:generate go run github.com/Alp4ka/gopager/cmd/gopager-gen -type User
+//
+// Flags:
+//
+//	-type     comma-separated struct names; all structs with sortable fields if empty
+//	-output   output file; defaults to sortable_gopager.go in the package directory
+//	-qualify  prefix column names with the table name, e.g. users.created_at
+//	-table    comma-separated table names of structs for -qualify, e.g. User=app_users
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	var (
+		types   = flag.String("type", "", "comma-separated struct names")
+		output  = flag.String("output", "", "output file name")
+		qualify = flag.Bool("qualify", false, "prefix column names with the table name")
+		tables  = flag.String("table", "", "comma-separated table names of structs, e.g. User=app_users")
+	)
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	cfg := config{dir: dir, qualify: *qualify}
+	if *types != "" {
+		cfg.types = strings.Split(*types, ",")
+	}
+	if *tables != "" {
+		var err error
+		cfg.tables, err = parseTables(*tables)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gopager-gen: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	src, err := generate(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gopager-gen: %s\n", err)
+		os.Exit(1)
+	}
+
+	path := *output
+	if path == "" {
+		path = outputPath(dir)
+	}
+
+	err = os.WriteFile(path, src, 0o644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gopager-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseTables parses the -table flag of the form "Type=table,...".
+func parseTables(value string) (map[string]string, error) {
+	ret := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		name, table, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" || table == "" {
+			return nil, fmt.Errorf("invalid table name '%s', expected Type=table", pair)
+		}
+
+		ret[name] = table
+	}
+
+	return ret, nil
+}
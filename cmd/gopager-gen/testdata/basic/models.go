@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type User struct {
+	ID        int64     `gorm:"primaryKey" gopager:"sortable"`
+	CreatedAt time.Time `gopager:"sortable,alias=created"`
+	Name      string    `gorm:"column:full_name;not null" gopager:"sortable,alias=name"`
+	Email     string
+}
+
+type Session struct {
+	Token string
+}
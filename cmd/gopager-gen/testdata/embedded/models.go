@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type Timestamps struct {
+	CreatedAt time.Time `gopager:"sortable,alias=created"`
+	UpdatedAt time.Time `gopager:"sortable,alias=updated"`
+}
+
+type Order struct {
+	Timestamps
+	ID     string  `gorm:"column:order_id" gopager:"sortable,alias=id"`
+	Amount float64 `gopager:"sortable"`
+}
+
+type Item struct {
+	*Timestamps
+	SKU string `gopager:"sortable"`
+}
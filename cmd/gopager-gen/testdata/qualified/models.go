@@ -0,0 +1,15 @@
+package models
+
+type Account struct {
+	ID      int64  `gopager:"sortable"`
+	Balance string `gopager:"sortable,alias=balance"`
+}
+
+type Transfer struct {
+	ID     int64 `gopager:"sortable"`
+	Amount int64 `gopager:"sortable"`
+}
+
+func (Transfer) TableName() string {
+	return "ledger_transfers"
+}
@@ -0,0 +1,173 @@
+package gopager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Count returns the number of records in the dataset queried by db, which
+// is the base query without pagination applied. The count runs in a session
+// cloned from db: ordering, limit, offset and preloads are removed, db itself
+// is not modified.
+//
+// Usage:
+//
+//	query := db.Model(&User{}).Where("active = ?", true)
+//	total, err := pager.Count(query)
+//	paged, err := pager.Paginate(query)
+func (c *CursorPager[CursorType]) Count(db *gorm.DB) (int64, error) {
+	var ret int64
+	err := countSession(db).Count(&ret).Error
+	if err != nil {
+		return 0, fmt.Errorf("cannot count: %w", err)
+	}
+
+	return ret, nil
+}
+
+// PaginateWithTotal fetches the page of the dataset queried by db together
+// with the total number of records, see CursorPager.Total. The page and the
+// total are queried concurrently, unless db runs in a transaction.
+//
+// Usage:
+//
+//	result, err := gopager.PaginateWithTotal(ctx, db.Model(&User{}).Where("active = ?", true), pager, getters)
+func PaginateWithTotal[T any](
+	ctx context.Context,
+	db *gorm.DB,
+	initialPager *CursorPager[*DefaultCursor],
+	getters Getters[T],
+) (*PaginationResult[T, *DefaultCursor], error) {
+	resultSet, total, accuracy, err := findWithTotal[T](ctx, db, initialPager)
+	if err != nil {
+		return nil, err
+	}
+
+	resultSet, cursor, err := NextPageCursor(initialPager, resultSet, getters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginationResult[T, *DefaultCursor]{
+		Items:         resultSet,
+		Total:         total,
+		TotalAccuracy: accuracy,
+		AppliedLimit:  initialPager.GetLimit(),
+		NextPageToken: cursor,
+	}, nil
+}
+
+// PaginatePseudoWithTotal works like PaginateWithTotal for LIMIT/OFFSET
+// pagination.
+func PaginatePseudoWithTotal[T any](
+	ctx context.Context,
+	db *gorm.DB,
+	initialPager *CursorPager[*PseudoCursor],
+) (*PaginationResult[T, *PseudoCursor], error) {
+	resultSet, total, accuracy, err := findWithTotal[T](ctx, db, initialPager)
+	if err != nil {
+		return nil, err
+	}
+
+	resultSet, cursor, err := NextPagePseudoCursor(initialPager, resultSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginationResult[T, *PseudoCursor]{
+		Items:         resultSet,
+		Total:         total,
+		TotalAccuracy: accuracy,
+		AppliedLimit:  initialPager.GetLimit(),
+		NextPageToken: cursor,
+	}, nil
+}
+
+// findWithTotal fetches the page and counts the dataset. The first error
+// cancels the other query and is returned.
+func findWithTotal[T any, CursorType Cursor](
+	ctx context.Context,
+	db *gorm.DB,
+	initialPager *CursorPager[CursorType],
+) ([]T, int64, TotalAccuracy, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	db = db.WithContext(ctx)
+	paged, err := initialPager.Paginate(db)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var (
+		resultSet []T
+		total     int64
+		accuracy  TotalAccuracy
+		failed    error
+		once      sync.Once
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			failed = err
+			cancel()
+		})
+	}
+	find := func() {
+		err := paged.Find(&resultSet).Error
+		if err != nil {
+			fail(fmt.Errorf("cannot fetch page: %w", err))
+		}
+	}
+	count := func() {
+		var err error
+		total, accuracy, err = initialPager.Total(db)
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	// Queries of a transaction share a single connection.
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		find()
+		if failed == nil {
+			count()
+		}
+	} else {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count()
+		}()
+		find()
+		wg.Wait()
+	}
+
+	// The first error is reported, not the cancellation it caused.
+	if failed != nil {
+		return nil, 0, "", failed
+	}
+
+	return resultSet, total, accuracy, nil
+}
+
+// countSession returns a session of db counting the records of the dataset.
+func countSession(db *gorm.DB) *gorm.DB {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// A session with a context clones the statement, so the clauses are
+	// removed from the copy only.
+	ret := db.Session(&gorm.Session{Context: ctx})
+	delete(ret.Statement.Clauses, clause.OrderBy{}.Name())
+	delete(ret.Statement.Clauses, clause.Limit{}.Name())
+	ret.Statement.Preloads = nil
+
+	return ret
+}
@@ -0,0 +1,99 @@
+package gopager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type testCountUser struct {
+	ID     int
+	Active bool
+}
+
+func newCountTestDB(t *testing.T) *gorm.DB {
+	db, err := newGORMSQLite()
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&testCountUser{}))
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, db.Create(&testCountUser{ID: i, Active: i%2 == 0}).Error)
+	}
+
+	return db
+}
+
+func Test_CursorPager_Count(t *testing.T) {
+	db := newCountTestDB(t)
+	sort := OrderBy{Column: "id", Direction: DirectionASC}
+	pager := NewCursorPager[*DefaultCursor]().WithLimit(2).WithSort(sort).
+		WithCursor(NewDefaultCursor(CursorElement{Column: "id", Value: 4, Operator: OperatorGT}))
+
+	query := db.Model(&testCountUser{}).Where("active = ?", true).Order("id DESC").Limit(1).Offset(3)
+	total, err := pager.Count(query)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, total)
+
+	// The base query keeps its clauses.
+	var ids []int
+	require.NoError(t, query.Pluck("id", &ids).Error)
+	require.Equal(t, []int{4}, ids)
+
+	_, err = pager.Count(db.Table("missing"))
+	require.Error(t, err)
+}
+
+func Test_PaginateWithTotal(t *testing.T) {
+	db := newCountTestDB(t)
+	getters := Getters[testCountUser]{"id": func(u testCountUser) any { return u.ID }}
+	sort := OrderBy{Column: "id", Direction: DirectionASC}
+	query := db.Model(&testCountUser{}).Where("active = ?", true)
+
+	pager := NewCursorPager[*DefaultCursor]().WithLimit(2).WithLookahead().WithSort(sort)
+	result, err := PaginateWithTotal(context.Background(), query, pager, getters)
+	require.NoError(t, err)
+	require.Equal(t, []testCountUser{{2, true}, {4, true}}, result.Items)
+	require.EqualValues(t, 5, result.Total)
+	require.Equal(t, 2, result.AppliedLimit)
+	require.NotNil(t, result.NextPageToken)
+
+	// The total does not depend on the cursor.
+	pager = pager.WithCursor(result.NextPageToken)
+	result, err = PaginateWithTotal(context.Background(), query, pager, getters)
+	require.NoError(t, err)
+	require.Equal(t, []testCountUser{{6, true}, {8, true}}, result.Items)
+	require.EqualValues(t, 5, result.Total)
+
+	t.Run("transaction", func(t *testing.T) {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			pager := NewCursorPager[*DefaultCursor]().WithLimit(2).WithLookahead().WithSort(sort)
+			result, err := PaginateWithTotal(context.Background(), tx.Model(&testCountUser{}), pager, getters)
+			require.NoError(t, err)
+			require.Len(t, result.Items, 2)
+			require.EqualValues(t, 10, result.Total)
+
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("pseudo cursor", func(t *testing.T) {
+		pager := NewCursorPager[*PseudoCursor]().WithLimit(2).WithLookahead().WithSort(sort).WithCursor(NewPseudoCursor(4))
+		result, err := PaginatePseudoWithTotal[testCountUser](context.Background(), query, pager)
+		require.NoError(t, err)
+		require.Equal(t, []testCountUser{{10, true}}, result.Items)
+		require.EqualValues(t, 5, result.Total)
+		require.Nil(t, result.NextPageToken)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := PaginateWithTotal(context.Background(), db.Table("missing"), pager, getters)
+		require.Error(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = PaginateWithTotal(ctx, query, pager, getters)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
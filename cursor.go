@@ -12,7 +12,30 @@ type Cursor interface {
 	String() string
 	IsEmpty() bool
 	Apply(*gorm.DB) *gorm.DB
+	// apply works like Apply with the options of the pager.
+	apply(db *gorm.DB, opts cursorOptions) *gorm.DB
+	// renderSQL renders the filter adding its values to args and returns it
+	// together with the offset of the dataset.
+	renderSQL(args *sqlArgs, opts cursorOptions) (string, int)
 	validate(orderings Orderings) error
+	// isBackward reports whether the cursor fetches the dataset backwards,
+	// i.e. with inverted orderings.
+	isBackward() bool
+	// isStart reports whether the cursor points to the beginning of the
+	// dataset.
+	isStart() bool
+}
+
+// cursorOptions are the pager options affecting how a cursor is applied.
+type cursorOptions struct {
+	// rowValues enables row value comparison where the dialect supports it.
+	rowValues bool
+	// sort are the orderings of the pager, inverted for a backward cursor.
+	// Empty if the cursor is applied on its own.
+	sort Orderings
+	// notNull are the columns known to hold no NULL values. Conditions on
+	// other columns select NULL values placed after the cursor.
+	notNull notNullColumns
 }
 
 // PaginationResult is a generic paginated result container.
@@ -21,6 +44,8 @@ type PaginationResult[T any, CursorType Cursor] struct {
 	Items []T
 	// Total number of elements.
 	Total int64
+	// TotalAccuracy tells whether Total is exact, estimated or a lower bound.
+	TotalAccuracy TotalAccuracy
 	// AppliedLimit effective limit used for the query.
 	AppliedLimit int
 	// NextPageToken token for the next page.
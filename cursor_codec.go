@@ -0,0 +1,251 @@
+package gopager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Identifiers of the built-in cursor formats. The format identifier is stored
+// in every token, so DecodeCursor detects the format automatically.
+const (
+	CursorFormatJSON   byte = 0x01
+	CursorFormatBinary byte = 0x02
+)
+
+// CursorCodec serializes cursor payloads. It defines the format of the token
+// content, while TokenCodec defines how the content is protected and wrapped
+// into a string.
+//
+// Custom implementations must return a unique Format identifier: it is stored
+// in tokens and used to select the codec on decode.
+type CursorCodec interface {
+	// Format returns the format identifier stored in tokens.
+	Format() byte
+	// MarshalElements serializes DefaultCursor elements.
+	MarshalElements(elements []CursorElement) ([]byte, error)
+	// UnmarshalElements parses DefaultCursor elements.
+	UnmarshalElements(data []byte) ([]CursorElement, error)
+	// MarshalOffset serializes PseudoCursor offset.
+	MarshalOffset(offset int) ([]byte, error)
+	// UnmarshalOffset parses PseudoCursor offset.
+	UnmarshalOffset(data []byte) (int, error)
+}
+
+var _builtinCursorCodecs = map[byte]CursorCodec{
+	CursorFormatJSON:   JSONCursorCodec{},
+	CursorFormatBinary: BinaryCursorCodec{},
+}
+
+// JSONCursorCodec encodes DefaultCursor elements as a JSON array and
+// PseudoCursor offsets as decimal numbers. This is the default format.
+//
+// Every value is stored with a type tag, so decoding restores its Go type:
+// int64, uint64, float64, string, bool, time.Time, []byte, UUID or Decimal.
+// Values of tokens issued without type tags are parsed heuristically: numbers
+// become float64 and RFC 3339 strings become time.Time.
+type JSONCursorCodec struct{}
+
+// Type tags of the JSON format.
+const (
+	_jsonTagInt64   = "i64"
+	_jsonTagUint64  = "u64"
+	_jsonTagFloat64 = "f64"
+	_jsonTagString  = "str"
+	_jsonTagBool    = "bool"
+	_jsonTagTime    = "ts"
+	_jsonTagBytes   = "bytes"
+	_jsonTagUUID    = "uuid"
+	_jsonTagDecimal = "dec"
+)
+
+type jsonCursorElement struct {
+	Column   string          `json:"c"`
+	Value    json.RawMessage `json:"v"`
+	Type     string          `json:"t,omitempty"`
+	Operator Operator        `json:"o"`
+}
+
+// Format - implements CursorCodec.
+func (JSONCursorCodec) Format() byte {
+	return CursorFormatJSON
+}
+
+// MarshalElements - implements CursorCodec.
+func (JSONCursorCodec) MarshalElements(elements []CursorElement) ([]byte, error) {
+	jElems := make([]jsonCursorElement, 0, len(elements))
+	for _, elem := range elements {
+		value, tag, err := marshalJSONValue(elem.Value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal cursor value of column '%s': %w", elem.Column, err)
+		}
+
+		jElems = append(jElems, jsonCursorElement{
+			Column:   elem.Column,
+			Value:    value,
+			Type:     tag,
+			Operator: elem.Operator,
+		})
+	}
+
+	jTok, err := json.Marshal(jElems)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal cursor value: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err = json.Compact(&buf, jTok); err != nil {
+		return nil, fmt.Errorf("cannot compact cursor value: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalElements - implements CursorCodec.
+func (JSONCursorCodec) UnmarshalElements(data []byte) ([]CursorElement, error) {
+	var jElems []jsonCursorElement
+	if err := json.Unmarshal(data, &jElems); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json encoded cursor: %w", err)
+	}
+
+	elems := make([]CursorElement, 0, len(jElems))
+	for _, jElem := range jElems {
+		value, err := unmarshalJSONValue(jElem.Value, jElem.Type)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal json encoded cursor value of column '%s': %w", jElem.Column, err)
+		}
+
+		elems = append(elems, CursorElement{
+			Column:   jElem.Column,
+			Value:    value,
+			Operator: jElem.Operator,
+		})
+	}
+
+	return elems, nil
+}
+
+// MarshalOffset - implements CursorCodec.
+func (JSONCursorCodec) MarshalOffset(offset int) ([]byte, error) {
+	return []byte(strconv.Itoa(offset)), nil
+}
+
+// UnmarshalOffset - implements CursorCodec.
+func (JSONCursorCodec) UnmarshalOffset(data []byte) (int, error) {
+	offset, err := strconv.Atoi(string(data))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode pseudo cursor offset value: %w", err)
+	}
+
+	return offset, nil
+}
+
+// marshalJSONValue returns JSON representation of a value and its type tag.
+func marshalJSONValue(v any) (json.RawMessage, string, error) {
+	v, err := normalizeCursorValue(v)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var tag string
+	switch vt := v.(type) {
+	case nil:
+	case int64:
+		tag = _jsonTagInt64
+	case uint64:
+		tag = _jsonTagUint64
+	case float64:
+		tag = _jsonTagFloat64
+	case string:
+		tag = _jsonTagString
+	case bool:
+		tag = _jsonTagBool
+	case time.Time:
+		tag, v = _jsonTagTime, vt.Format(time.RFC3339Nano)
+	case []byte:
+		tag = _jsonTagBytes
+	case UUID:
+		tag, v = _jsonTagUUID, vt.String()
+	case Decimal:
+		tag, v = _jsonTagDecimal, string(vt)
+	default:
+		return nil, "", fmt.Errorf("unsupported value type %T", v)
+	}
+
+	ret, err := json.Marshal(v)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return ret, tag, nil
+}
+
+// unmarshalJSONValue restores a value from its JSON representation and type tag.
+func unmarshalJSONValue(data json.RawMessage, tag string) (any, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	var (
+		err error
+		ret any
+	)
+	switch tag {
+	case "":
+		// Tokens issued before type tags were introduced.
+		err = json.Unmarshal(data, &ret)
+		ret = parseAnyValue(ret)
+	case _jsonTagInt64:
+		ret, err = unmarshalJSONNumber(data, func(s string) (any, error) { return strconv.ParseInt(s, 10, 64) })
+	case _jsonTagUint64:
+		ret, err = unmarshalJSONNumber(data, func(s string) (any, error) { return strconv.ParseUint(s, 10, 64) })
+	case _jsonTagFloat64:
+		ret, err = unmarshalJSONNumber(data, func(s string) (any, error) { return strconv.ParseFloat(s, 64) })
+	case _jsonTagString:
+		ret, err = unmarshalJSONString(data, func(s string) (any, error) { return s, nil })
+	case _jsonTagBool:
+		var b bool
+		err = json.Unmarshal(data, &b)
+		ret = b
+	case _jsonTagTime:
+		ret, err = unmarshalJSONString(data, func(s string) (any, error) { return time.Parse(time.RFC3339Nano, s) })
+	case _jsonTagBytes:
+		var b []byte
+		err = json.Unmarshal(data, &b)
+		ret = b
+	case _jsonTagUUID:
+		ret, err = unmarshalJSONString(data, func(s string) (any, error) { return ParseUUID(s) })
+	case _jsonTagDecimal:
+		ret, err = unmarshalJSONString(data, func(s string) (any, error) { return Decimal(s), nil })
+	default:
+		return nil, fmt.Errorf("unknown value type tag '%s'", tag)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func unmarshalJSONNumber(data json.RawMessage, parse func(string) (any, error)) (any, error) {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, err
+	}
+
+	return parse(n.String())
+}
+
+func unmarshalJSONString(data json.RawMessage, parse func(string) (any, error)) (any, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	return parse(s)
+}
+
+var _ CursorCodec = JSONCursorCodec{}
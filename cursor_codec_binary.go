@@ -0,0 +1,295 @@
+package gopager
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var errBinaryCursorTruncated = errors.New("binary encoded cursor is truncated")
+
+// Value tags of the binary format.
+const (
+	_binaryTagNil byte = iota
+	_binaryTagFalse
+	_binaryTagTrue
+	_binaryTagInt64
+	_binaryTagUint64
+	_binaryTagFloat64
+	_binaryTagString
+	_binaryTagBytes
+	_binaryTagTime
+	_binaryTagUUID
+	_binaryTagDecimal
+)
+
+// Operator tags of the binary format.
+const (
+	_binaryOperatorGT byte = iota
+	_binaryOperatorLT
+)
+
+// BinaryCursorCodec encodes cursors in a compact binary format. Integers and
+// timestamps take a few bytes instead of their JSON text representation, which
+// significantly reduces token length.
+//
+// Values are restored with their type: signed integers as int64, unsigned
+// integers as uint64, floats as float64, timestamps as time.Time in UTC,
+// [16]byte based types as UUID. Other values implementing driver.Valuer are
+// stored as the result of Value().
+//
+// Layout:
+//
+//	<count: uvarint>{<column: uvarint length + bytes><operator: 1 byte><value tag: 1 byte><value>}
+type BinaryCursorCodec struct{}
+
+// Format - implements CursorCodec.
+func (BinaryCursorCodec) Format() byte {
+	return CursorFormatBinary
+}
+
+// MarshalElements - implements CursorCodec.
+func (BinaryCursorCodec) MarshalElements(elements []CursorElement) ([]byte, error) {
+	ret := binary.AppendUvarint(nil, uint64(len(elements)))
+
+	var err error
+	for _, elem := range elements {
+		ret = appendBinaryString(ret, elem.Column)
+
+		switch elem.Operator {
+		case OperatorGT:
+			ret = append(ret, _binaryOperatorGT)
+		case OperatorLT:
+			ret = append(ret, _binaryOperatorLT)
+		default:
+			return nil, fmt.Errorf("cannot marshal cursor operator '%s'", elem.Operator)
+		}
+
+		ret, err = appendBinaryValue(ret, elem.Value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal cursor value of column '%s': %w", elem.Column, err)
+		}
+	}
+
+	return ret, nil
+}
+
+// UnmarshalElements - implements CursorCodec.
+func (BinaryCursorCodec) UnmarshalElements(data []byte) ([]CursorElement, error) {
+	r := binaryReader{data: data}
+
+	count := r.uvarint()
+	if r.err == nil && count > uint64(len(data)) {
+		return nil, errBinaryCursorTruncated
+	}
+
+	elems := make([]CursorElement, 0, count)
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		var elem CursorElement
+		elem.Column = r.string()
+
+		switch op := r.byte(); op {
+		case _binaryOperatorGT:
+			elem.Operator = OperatorGT
+		case _binaryOperatorLT:
+			elem.Operator = OperatorLT
+		default:
+			r.fail(fmt.Errorf("unknown binary cursor operator %d", op))
+		}
+
+		elem.Value = r.value()
+		elems = append(elems, elem)
+	}
+
+	if r.err == nil && len(r.data) != 0 {
+		r.fail(fmt.Errorf("unexpected trailing bytes in binary encoded cursor"))
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to unmarshal binary encoded cursor: %w", r.err)
+	}
+
+	return elems, nil
+}
+
+// MarshalOffset - implements CursorCodec.
+func (BinaryCursorCodec) MarshalOffset(offset int) ([]byte, error) {
+	return binary.AppendVarint(nil, int64(offset)), nil
+}
+
+// UnmarshalOffset - implements CursorCodec.
+func (BinaryCursorCodec) UnmarshalOffset(data []byte) (int, error) {
+	offset, n := binary.Varint(data)
+	if n <= 0 || n != len(data) {
+		return 0, fmt.Errorf("failed to decode pseudo cursor offset value")
+	}
+
+	return int(offset), nil
+}
+
+func appendBinaryString(dst []byte, s string) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(s)))
+	return append(dst, s...)
+}
+
+func appendBinaryValue(dst []byte, v any) ([]byte, error) {
+	v, err := normalizeCursorValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	switch vt := v.(type) {
+	case nil:
+		return append(dst, _binaryTagNil), nil
+	case bool:
+		if vt {
+			return append(dst, _binaryTagTrue), nil
+		}
+		return append(dst, _binaryTagFalse), nil
+	case int64:
+		return binary.AppendVarint(append(dst, _binaryTagInt64), vt), nil
+	case uint64:
+		return binary.AppendUvarint(append(dst, _binaryTagUint64), vt), nil
+	case float64:
+		return binary.BigEndian.AppendUint64(append(dst, _binaryTagFloat64), math.Float64bits(vt)), nil
+	case string:
+		return appendBinaryString(append(dst, _binaryTagString), vt), nil
+	case []byte:
+		dst = binary.AppendUvarint(append(dst, _binaryTagBytes), uint64(len(vt)))
+		return append(dst, vt...), nil
+	case time.Time:
+		dst = binary.AppendVarint(append(dst, _binaryTagTime), vt.Unix())
+		return binary.AppendUvarint(dst, uint64(vt.Nanosecond())), nil
+	case UUID:
+		return append(append(dst, _binaryTagUUID), vt[:]...), nil
+	case Decimal:
+		return appendBinaryString(append(dst, _binaryTagDecimal), string(vt)), nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+// binaryReader reads the binary format and remembers the first error.
+// Once an error occurs, every subsequent read returns a zero value.
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+func (r *binaryReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *binaryReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data) {
+		r.fail(errBinaryCursorTruncated)
+		return nil
+	}
+
+	ret := r.data[:n]
+	r.data = r.data[n:]
+
+	return ret
+}
+
+func (r *binaryReader) byte() byte {
+	b := r.next(1)
+	if b == nil {
+		return 0
+	}
+
+	return b[0]
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail(errBinaryCursorTruncated)
+		return 0
+	}
+	r.data = r.data[n:]
+
+	return v
+}
+
+func (r *binaryReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.fail(errBinaryCursorTruncated)
+		return 0
+	}
+	r.data = r.data[n:]
+
+	return v
+}
+
+func (r *binaryReader) bytes() []byte {
+	n := r.uvarint()
+	if n > uint64(len(r.data)) {
+		r.fail(errBinaryCursorTruncated)
+		return nil
+	}
+
+	return r.next(int(n))
+}
+
+func (r *binaryReader) string() string {
+	return string(r.bytes())
+}
+
+func (r *binaryReader) value() any {
+	switch tag := r.byte(); tag {
+	case _binaryTagNil:
+		return nil
+	case _binaryTagFalse:
+		return false
+	case _binaryTagTrue:
+		return true
+	case _binaryTagInt64:
+		return r.varint()
+	case _binaryTagUint64:
+		return r.uvarint()
+	case _binaryTagFloat64:
+		b := r.next(8)
+		if b == nil {
+			return nil
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b))
+	case _binaryTagString:
+		return r.string()
+	case _binaryTagBytes:
+		return append([]byte{}, r.bytes()...)
+	case _binaryTagTime:
+		sec, nsec := r.varint(), r.uvarint()
+		if nsec >= uint64(time.Second) {
+			r.fail(fmt.Errorf("invalid binary encoded time"))
+			return nil
+		}
+		return time.Unix(sec, int64(nsec)).UTC()
+	case _binaryTagUUID:
+		var ret UUID
+		copy(ret[:], r.next(len(ret)))
+		return ret
+	case _binaryTagDecimal:
+		return Decimal(r.string())
+	default:
+		r.fail(fmt.Errorf("unknown binary cursor value tag %d", tag))
+		return nil
+	}
+}
+
+var _ CursorCodec = BinaryCursorCodec{}
@@ -0,0 +1,213 @@
+package gopager
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BinaryCursorCodec_Elements(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	id := int64(math.MaxInt64 - 1)
+
+	tests := []struct {
+		name     string
+		elements []CursorElement
+		expected []CursorElement
+	}{
+		{
+			name: "typed values",
+			elements: []CursorElement{
+				{Column: "id", Value: id, Operator: OperatorGT},
+				{Column: "small", Value: int8(-3), Operator: OperatorLT},
+				{Column: "counter", Value: uint64(math.MaxUint64), Operator: OperatorGT},
+				{Column: "price", Value: float32(1.5), Operator: OperatorGT},
+				{Column: "name", Value: "2024-01-01T00:00:00Z", Operator: OperatorLT},
+				{Column: "active", Value: true, Operator: OperatorGT},
+				{Column: "deleted", Value: false, Operator: OperatorGT},
+				{Column: "created_at", Value: ts, Operator: OperatorLT},
+				{Column: "hash", Value: []byte{0, 1, 2}, Operator: OperatorGT},
+				{Column: "parent_id", Value: nil, Operator: OperatorGT},
+			},
+			expected: []CursorElement{
+				{Column: "id", Value: id, Operator: OperatorGT},
+				{Column: "small", Value: int64(-3), Operator: OperatorLT},
+				{Column: "counter", Value: uint64(math.MaxUint64), Operator: OperatorGT},
+				{Column: "price", Value: float64(1.5), Operator: OperatorGT},
+				{Column: "name", Value: "2024-01-01T00:00:00Z", Operator: OperatorLT},
+				{Column: "active", Value: true, Operator: OperatorGT},
+				{Column: "deleted", Value: false, Operator: OperatorGT},
+				{Column: "created_at", Value: ts, Operator: OperatorLT},
+				{Column: "hash", Value: []byte{0, 1, 2}, Operator: OperatorGT},
+				{Column: "parent_id", Value: nil, Operator: OperatorGT},
+			},
+		},
+		{
+			name: "pointers and local time",
+			elements: []CursorElement{
+				{Column: "id", Value: &id, Operator: OperatorGT},
+				{Column: "nil_ptr", Value: (*int64)(nil), Operator: OperatorGT},
+				{Column: "created_at", Value: ts.In(time.FixedZone("X", 3600)), Operator: OperatorGT},
+			},
+			expected: []CursorElement{
+				{Column: "id", Value: id, Operator: OperatorGT},
+				{Column: "nil_ptr", Value: nil, Operator: OperatorGT},
+				{Column: "created_at", Value: ts, Operator: OperatorGT},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := BinaryCursorCodec{}.MarshalElements(tt.elements)
+			require.NoError(t, err)
+
+			got, err := BinaryCursorCodec{}.UnmarshalElements(data)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, got)
+
+			for i := 0; i < len(data); i++ {
+				_, err = BinaryCursorCodec{}.UnmarshalElements(data[:i])
+				require.Error(t, err, "truncated at %d", i)
+			}
+		})
+	}
+}
+
+func Test_CursorCodec_MarshalErrors(t *testing.T) {
+	elements := []CursorElement{{Column: "id", Value: make(chan int), Operator: OperatorGT}}
+
+	for _, codec := range []CursorCodec{JSONCursorCodec{}, BinaryCursorCodec{}} {
+		_, err := codec.MarshalElements(elements)
+		require.Error(t, err)
+
+		c := NewDefaultCursor(elements...).WithTokenCodec(NewTokenCodec().WithCursorCodec(codec))
+		_, err = c.Encode()
+		require.Error(t, err)
+		require.NotPanics(t, func() { _ = c.String() })
+		require.NotEmpty(t, c.String())
+		_, err = DecodeCursor(c.String())
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+}
+
+func Test_NextPageCursor_ReturnsEncodingError(t *testing.T) {
+	type item struct{ Ch chan int }
+
+	pager := NewCursorPager[*DefaultCursor]().
+		WithLimit(1).
+		WithSort(OrderBy{Column: "ch", Direction: DirectionASC})
+
+	_, _, err := NextPageCursor(pager, []item{{}}, Getters[item]{"ch": func(i item) any { return i.Ch }})
+	require.Error(t, err)
+}
+
+func Test_TokenCodec_CursorFormatDetection(t *testing.T) {
+	elements := []CursorElement{
+		{Column: "id", Value: int64(1) << 60, Operator: OperatorGT},
+		{Column: "created_at", Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Operator: OperatorLT},
+	}
+	binaryCodec := NewTokenCodec().WithCursorCodec(BinaryCursorCodec{})
+
+	jsonToken := NewDefaultCursor(elements...).String()
+	binaryToken := NewDefaultCursor(elements...).WithTokenCodec(binaryCodec).String()
+	require.Less(t, len(binaryToken), len(jsonToken))
+
+	// Any codec reads built-in formats.
+	decoded, err := DecodeCursor(binaryToken)
+	require.NoError(t, err)
+	require.Equal(t, elements, decoded.GetElements())
+
+	decoded, err = binaryCodec.DecodeCursor(jsonToken)
+	require.NoError(t, err)
+	require.Len(t, decoded.GetElements(), 2)
+
+	pseudoToken := NewPseudoCursor(1000).WithTokenCodec(binaryCodec).String()
+	pseudo, err := DecodePseudoCursor(pseudoToken)
+	require.NoError(t, err)
+	require.Equal(t, 1000, pseudo.GetOffset())
+
+	// Unknown formats are rejected.
+	unknown := _encoder.EncodeToString(tokenEnvelope{format: 0x7F, body: []byte("1")}.marshal())
+	_, err = DecodePseudoCursor(unknown)
+	require.Error(t, err)
+}
+
+func Test_BinaryCursorCodec_Offset(t *testing.T) {
+	for _, offset := range []int{1, 127, 128, math.MaxInt32, -5} {
+		data, err := BinaryCursorCodec{}.MarshalOffset(offset)
+		require.NoError(t, err)
+
+		got, err := BinaryCursorCodec{}.UnmarshalOffset(data)
+		require.NoError(t, err)
+		require.Equal(t, offset, got)
+	}
+
+	_, err := BinaryCursorCodec{}.UnmarshalOffset(nil)
+	require.Error(t, err)
+}
+
+func Test_CursorCodec_TypedValues(t *testing.T) {
+	type status string
+
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	uuid, err := ParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	require.NoError(t, err)
+
+	elements := []CursorElement{
+		{Column: "id", Value: int64(1<<53 + 1), Operator: OperatorGT},
+		{Column: "counter", Value: uint64(math.MaxUint64), Operator: OperatorGT},
+		{Column: "price", Value: 0.1, Operator: OperatorGT},
+		{Column: "name", Value: "2024-01-01T00:00:00Z", Operator: OperatorLT},
+		{Column: "status", Value: status("active"), Operator: OperatorLT},
+		{Column: "active", Value: true, Operator: OperatorGT},
+		{Column: "created_at", Value: ts, Operator: OperatorLT},
+		{Column: "hash", Value: []byte{0, 1, 2}, Operator: OperatorGT},
+		{Column: "uuid", Value: testUUID(uuid), Operator: OperatorGT},
+		{Column: "balance", Value: Decimal("12345678901234567890.000000001"), Operator: OperatorGT},
+		{Column: "parent_id", Value: nil, Operator: OperatorGT},
+	}
+	expected := []CursorElement{
+		{Column: "id", Value: int64(1<<53 + 1), Operator: OperatorGT},
+		{Column: "counter", Value: uint64(math.MaxUint64), Operator: OperatorGT},
+		{Column: "price", Value: 0.1, Operator: OperatorGT},
+		{Column: "name", Value: "2024-01-01T00:00:00Z", Operator: OperatorLT},
+		{Column: "status", Value: "active", Operator: OperatorLT},
+		{Column: "active", Value: true, Operator: OperatorGT},
+		{Column: "created_at", Value: ts, Operator: OperatorLT},
+		{Column: "hash", Value: []byte{0, 1, 2}, Operator: OperatorGT},
+		{Column: "uuid", Value: uuid, Operator: OperatorGT},
+		{Column: "balance", Value: Decimal("12345678901234567890.000000001"), Operator: OperatorGT},
+		{Column: "parent_id", Value: nil, Operator: OperatorGT},
+	}
+
+	for _, codec := range []CursorCodec{JSONCursorCodec{}, BinaryCursorCodec{}} {
+		t.Run(fmt.Sprintf("%T", codec), func(t *testing.T) {
+			token := NewDefaultCursor(elements...).WithTokenCodec(NewTokenCodec().WithCursorCodec(codec)).String()
+
+			decoded, err := DecodeCursor(token)
+			require.NoError(t, err)
+			require.Equal(t, expected, decoded.GetElements())
+		})
+	}
+}
+
+func Test_JSONCursorCodec_UntaggedValues(t *testing.T) {
+	elements, err := JSONCursorCodec{}.UnmarshalElements([]byte(
+		`[{"c":"id","v":5,"o":">"},{"c":"created_at","v":"2024-01-02T03:04:05Z","o":">"},{"c":"name","v":"abc","o":"<"}]`,
+	))
+	require.NoError(t, err)
+	require.Equal(t, []CursorElement{
+		{Column: "id", Value: float64(5), Operator: OperatorGT},
+		{Column: "created_at", Value: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Operator: OperatorGT},
+		{Column: "name", Value: "abc", Operator: OperatorLT},
+	}, elements)
+
+	_, err = JSONCursorCodec{}.UnmarshalElements([]byte(`[{"c":"id","v":5,"t":"???","o":">"}]`))
+	require.Error(t, err)
+
+	_, err = JSONCursorCodec{}.UnmarshalElements([]byte(`[{"c":"id","v":"x","t":"i64","o":">"}]`))
+	require.Error(t, err)
+}
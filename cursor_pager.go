@@ -1,13 +1,20 @@
 package gopager
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"slices"
+	"time"
 
 	"github.com/samber/lo"
 	"gorm.io/gorm"
 )
 
+// ErrLookaheadUnlimited is returned when lookahead is enabled for unlimited
+// paging.
+var ErrLookaheadUnlimited = errors.New("cannot apply lookahead to unlimited paging")
+
 // RawCursorPager is intended for API payloads. For proper code generation, inline it:
 //
 //	type MyFilter struct {
@@ -16,7 +23,7 @@ import (
 type RawCursorPager struct {
 	// Limit - maximum number of records to return in the response.
 	Limit int `json:"limit"`
-	// StartToken - base64-encoded cursor token obtained via Cursor.String().
+	// StartToken - base64-encoded cursor token obtained via Encode.
 	// If empty, the first page with Limit records is returned.
 	StartToken string `json:"startToken"`
 }
@@ -28,6 +35,11 @@ func (p RawCursorPager) Decode(orderBy ...OrderBy) (*CursorPager[*DefaultCursor]
 	return DecodeCursorPager(p.Limit, p.StartToken, orderBy...)
 }
 
+// DecodeWithCodec works like Decode, but parses StartToken with the given codec.
+func (p RawCursorPager) DecodeWithCodec(codec *TokenCodec, orderBy ...OrderBy) (*CursorPager[*DefaultCursor], error) {
+	return codec.DecodeCursorPager(p.Limit, p.StartToken, orderBy...)
+}
+
 // DecodePseudo converts RawCursorPager into *CursorPager[*PseudoCursor], normalizing
 // Limit and validating StartToken. Returns *CursorPager[*PseudoCursor] with
 // WithSort applied.
@@ -35,11 +47,37 @@ func (p RawCursorPager) DecodePseudo(orderBy ...OrderBy) (*CursorPager[*PseudoCu
 	return DecodePseudoCursorPager(p.Limit, p.StartToken, orderBy...)
 }
 
+// DecodePseudoWithCodec works like DecodePseudo, but parses StartToken with the given codec.
+func (p RawCursorPager) DecodePseudoWithCodec(
+	codec *TokenCodec,
+	orderBy ...OrderBy,
+) (*CursorPager[*PseudoCursor], error) {
+	return codec.DecodePseudoCursorPager(p.Limit, p.StartToken, orderBy...)
+}
+
 type CursorPager[CursorType Cursor] struct {
-	lookahead bool
-	limit     int
-	cursor    CursorType
-	sort      Orderings
+	lookahead   bool
+	limit       int
+	cursor      CursorType
+	sort        Orderings
+	codec       *TokenCodec
+	tokenTTL    time.Duration
+	fingerprint []byte
+	rowValues   bool
+	// uniqueColumns are the declared unique columns. If set, the orderings
+	// must contain one of them.
+	uniqueColumns []string
+	// notNullColumns are the declared NOT NULL columns.
+	notNullColumns []string
+	// tieBreaker is appended to the orderings if they contain no unique
+	// column.
+	tieBreaker Orderings
+	// primaryKeys are the primary keys resolved by Paginate. If set, the
+	// tie-breaker is the primary key of the query model.
+	primaryKeys   *primaryKeyTieBreakers
+	totalStrategy TotalStrategy
+	// totalCap is the number of records counted by TotalCapped.
+	totalCap int
 }
 
 func NewCursorPager[CursorType Cursor]() *CursorPager[CursorType] {
@@ -50,28 +88,14 @@ func NewCursorPager[CursorType Cursor]() *CursorPager[CursorType] {
 //
 // Usage guide: https://doc.office.lan/spaces/MBCSHCH/pages/417057947
 func DecodeCursorPager(limit int, rawStartToken string, orderBy ...OrderBy) (*CursorPager[*DefaultCursor], error) {
-	cursor, err := DecodeCursor(rawStartToken)
-	if err != nil {
-		return nil, err
-	}
-
-	return (&CursorPager[*DefaultCursor]{
-		cursor: cursor,
-	}).WithSubstitutedSort(orderBy...).WithLimit(limit), nil
+	return (*TokenCodec)(nil).DecodeCursorPager(limit, rawStartToken, orderBy...)
 }
 
 // DecodePseudoCursorPager decodes a pseudo-cursor token into *CursorPager.
 //
 // Usage guide: https://doc.office.lan/spaces/MBCSHCH/pages/417057947
 func DecodePseudoCursorPager(limit int, rawStartToken string, orderBy ...OrderBy) (*CursorPager[*PseudoCursor], error) {
-	cursor, err := DecodePseudoCursor(rawStartToken)
-	if err != nil {
-		return nil, err
-	}
-
-	return (&CursorPager[*PseudoCursor]{
-		cursor: cursor,
-	}).WithSubstitutedSort(orderBy...).WithLimit(limit), nil
+	return (*TokenCodec)(nil).DecodePseudoCursorPager(limit, rawStartToken, orderBy...)
 }
 
 // WithLookahead enables lookahead pagination, which checks the next page to
@@ -103,6 +127,20 @@ func (c *CursorPager[CursorType]) WithUnlimited() *CursorPager[CursorType] {
 	return c
 }
 
+// WithZeroLimit sets the limit to zero: the page has no records. Unlike
+// WithLimit(0), which applies DefaultLimit, it is used when the client
+// explicitly asks for an empty page. With lookahead one record is still
+// fetched to tell whether there is a next page.
+func (c *CursorPager[CursorType]) WithZeroLimit() *CursorPager[CursorType] {
+	if c == nil {
+		c = new(CursorPager[CursorType])
+	}
+
+	c.limit = 0
+
+	return c
+}
+
 // WithLimit sets the maximum number of returned records.
 //
 // IMPORTANT:
@@ -132,6 +170,117 @@ func (c *CursorPager[CursorType]) WithCursor(cursor CursorType) *CursorPager[Cur
 	return c
 }
 
+// WithTokenCodec sets the codec used to encode cursors built for the next pages.
+func (c *CursorPager[CursorType]) WithTokenCodec(codec *TokenCodec) *CursorPager[CursorType] {
+	if c == nil {
+		c = new(CursorPager[CursorType])
+	}
+
+	c.codec = codec
+
+	return c
+}
+
+// WithTokenTTL sets the lifetime of cursors built for the next pages. Expired
+// tokens are rejected on decode with ErrCursorExpired. Zero TTL means tokens
+// never expire.
+//
+// IMPORTANT:
+// Plain tokens can be modified by clients. Use a TokenCodec with a signer or
+// a cipher to enforce expiration.
+func (c *CursorPager[CursorType]) WithTokenTTL(ttl time.Duration) *CursorPager[CursorType] {
+	if c == nil {
+		c = new(CursorPager[CursorType])
+	}
+
+	c.tokenTTL = ttl
+
+	return c
+}
+
+// WithRowValueComparison renders the cursor filter as a row value comparison,
+// e.g. (a, b) > (?, ?) instead of (a > ?) OR (a = ? AND b > ?), which lets
+// the database use a composite index on the ordering columns.
+//
+// Row values are used only if every ordering has the same direction and the
+// dialect supports them (PostgreSQL, MySQL and SQLite). Otherwise the filter
+// falls back to the expanded form.
+func (c *CursorPager[CursorType]) WithRowValueComparison() *CursorPager[CursorType] {
+	if c == nil {
+		c = new(CursorPager[CursorType])
+	}
+
+	c.rowValues = true
+
+	return c
+}
+
+// IsRowValueComparison returns true if row value comparison is enabled.
+func (c *CursorPager[CursorType]) IsRowValueComparison() bool {
+	if c == nil {
+		return false
+	}
+
+	return c.rowValues
+}
+
+// WithUniqueColumns declares columns with a unique constraint. Pagination
+// fails if the orderings contain none of them and no tie-breaker is set:
+// without a unique column, rows with equal sort values are skipped.
+func (c *CursorPager[CursorType]) WithUniqueColumns(columns ...string) *CursorPager[CursorType] {
+	if c == nil {
+		c = new(CursorPager[CursorType])
+	}
+
+	c.uniqueColumns = append(c.uniqueColumns, columns...)
+
+	return c
+}
+
+// WithNotNullColumns declares columns which hold no NULL values. Keyset
+// conditions select NULL values placed after the cursor with IS NULL unless
+// the column is declared NOT NULL, is the last ordering column, or is a
+// primary key or a NOT NULL field of the query model. Declaring columns keeps
+// conditions index friendly and allows row value comparison.
+func (c *CursorPager[CursorType]) WithNotNullColumns(columns ...string) *CursorPager[CursorType] {
+	if c == nil {
+		c = new(CursorPager[CursorType])
+	}
+
+	c.notNullColumns = append(c.notNullColumns, columns...)
+
+	return c
+}
+
+// WithTieBreaker sets orderings by a unique key, e.g. the primary key, which
+// are appended to the sort if it contains neither a unique column nor the
+// whole key. Several orderings form a composite key.
+func (c *CursorPager[CursorType]) WithTieBreaker(orderBy ...OrderBy) *CursorPager[CursorType] {
+	if c == nil {
+		c = new(CursorPager[CursorType])
+	}
+
+	c.tieBreaker = orderBy
+
+	return c
+}
+
+// WithPrimaryKeyTieBreaker sets the primary key of the query model as the
+// tie-breaker. The key is resolved from db.Statement.Model by every Paginate
+// call without modifying the pager, and NextPageCursor, PrevPageCursor and
+// CursorForRow take the key of the model of the rows, so call them after
+// Paginate. The key columns are qualified with the table name and sorted in
+// the direction of the last ordering.
+func (c *CursorPager[CursorType]) WithPrimaryKeyTieBreaker() *CursorPager[CursorType] {
+	if c == nil {
+		c = new(CursorPager[CursorType])
+	}
+
+	c.primaryKeys = new(primaryKeyTieBreakers)
+
+	return c
+}
+
 // WithSubstitutedSort resets previous orderings and applies the provided ones.
 func (c *CursorPager[CursorType]) WithSubstitutedSort(orderBy ...OrderBy) *CursorPager[CursorType] {
 	if c == nil {
@@ -175,30 +324,188 @@ func (c *CursorPager[CursorType]) Paginate(db *gorm.DB) (*gorm.DB, error) {
 		c = new(CursorPager[CursorType])
 	}
 
-	err := c.validate()
+	c, err := c.prepare(db)
 	if err != nil {
 		return nil, fmt.Errorf("cannot paginate: %w", err)
 	}
 
-	db = c.sort.Apply(db)
-	db = c.cursor.Apply(db)
+	// Backward cursor fetches the previous page: the dataset is read in the
+	// inverted order and reordered back by TrimResultSet.
+	sort := c.orderings()
+	if c.cursor.isBackward() {
+		sort = sort.Invert()
+	}
+	db = sort.Apply(db)
+	db = c.cursor.apply(db, c.cursorOptions(db, sort))
 
 	// Apply limit to the dataset. When lookahead is enabled, fetch one extra
 	// record to determine if there is a next page.
 	if c.limit != NoLimit {
 		db = db.Limit(c.GetDatasetLimit())
 	}
+	// A zero limit cannot be rendered in every dialect, e.g. SQL Server
+	// rejects FETCH NEXT 0 ROWS.
+	if c.limit != NoLimit && c.GetDatasetLimit() == 0 {
+		db = db.Where(_falseCondition)
+	}
 
 	return db, nil
 }
 
-// GetSort returns orderings that will be applied to the dataset.
+// cursorOptions returns the options the cursor is applied with. NOT NULL
+// columns are taken from the model of db, if any, and from the declared ones.
+func (c *CursorPager[_]) cursorOptions(db *gorm.DB, sort Orderings) cursorOptions {
+	notNull := modelNotNullColumns(db)
+	notNull.declared = c.notNullColumns
+
+	return cursorOptions{rowValues: c.rowValues, sort: sort, notNull: notNull}
+}
+
+// prepare resolves the primary key tie-breaker from the model of db and
+// validates the pager for the dialect of db. Returns a copy of the pager with
+// the resolved tie-breaker, the pager itself is not modified.
+func (c *CursorPager[CursorType]) prepare(db *gorm.DB) (*CursorPager[CursorType], error) {
+	if c.primaryKeys != nil && len(c.tieBreaker) == 0 {
+		// The key is sorted in the direction of the last ordering.
+		err := c.sort.validate()
+		if err != nil {
+			return nil, err
+		}
+
+		typ, columns, err := primaryKeyColumns(db)
+		if err != nil {
+			return nil, err
+		}
+
+		c.primaryKeys.store(typ, columns)
+		c = c.withPrimaryKey(columns)
+	}
+
+	err := c.validate()
+	if err == nil {
+		err = c.orderings().validateDialect(gormDialectOf(db))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// forRows returns a copy of the pager with the primary key tie-breaker of the
+// model of rows of the type resolved by Paginate. The pager is returned as is
+// if the tie-breaker is not the primary key or was not resolved yet.
+func (c *CursorPager[CursorType]) forRows(typ reflect.Type) *CursorPager[CursorType] {
+	if c == nil || c.primaryKeys == nil || len(c.tieBreaker) != 0 {
+		return c
+	}
+
+	columns, ok := c.primaryKeys.load(typ)
+	if !ok {
+		return c
+	}
+
+	return c.withPrimaryKey(columns)
+}
+
+// withPrimaryKey returns a copy of the pager with the tie-breaker by the
+// primary key columns.
+func (c *CursorPager[CursorType]) withPrimaryKey(columns []string) *CursorPager[CursorType] {
+	direction := lo.LastOrEmpty(c.sort).Direction
+	ret := *c
+	ret.tieBreaker = lo.Map(columns, func(item string, _ int) OrderBy {
+		return OrderBy{Column: item, Direction: direction}
+	})
+
+	return &ret
+}
+
+// ToSQL renders pagination for a raw query in the dialect: the keyset
+// condition, the orderings and the limit. Columns are quoted for the dialect.
+// Placeholders are numbered starting from argIndex, so the fragment can be
+// merged into a query with other arguments. Returns an error if pagination
+// cannot be applied.
+//
+// Usage:
+//
+//	f, err := pager.ToSQL(gopager.DialectPostgres, 2)
+//	query := fmt.Sprintf(
+//		"SELECT * FROM users WHERE name = $1 AND %s ORDER BY %s %s",
+//		f.Where, f.OrderBy, f.LimitOffset,
+//	)
+//	rows, err := conn.Query(ctx, query, append([]any{name}, f.Args...)...)
+func (c *CursorPager[CursorType]) ToSQL(dialect Dialect, argIndex int) (*SQLFragment, error) {
+	err := c.validate()
+	if err == nil {
+		err = c.orderings().validateDialect(dialect)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot render pagination: %w", err)
+	}
+
+	sort := c.orderings()
+	if c.cursor.isBackward() {
+		sort = sort.Invert()
+	}
+
+	args := newSQLArgs(dialect, argIndex)
+	where, offset := c.cursor.renderSQL(args, c.cursorOptions(nil, sort))
+
+	limit := NoLimit
+	if c.limit != NoLimit {
+		limit = c.GetDatasetLimit()
+	}
+	// The page is empty: the limit alone cannot be rendered in every dialect.
+	if limit == 0 {
+		where, args.values = _falseCondition, nil
+	}
+
+	return &SQLFragment{
+		Where:       where,
+		OrderBy:     sort.ToDialectSQL(dialect),
+		LimitOffset: dialect.LimitOffset(limit, offset),
+		Args:        args.values,
+	}, nil
+}
+
+// GetSort returns orderings that will be applied to the dataset, including
+// the tie-breaker.
 func (c *CursorPager[CursorType]) GetSort() Orderings {
 	if c == nil {
 		return nil
 	}
 
-	return c.sort
+	return c.orderings()
+}
+
+// orderings returns the sort with the tie-breaker appended if the sort
+// contains no unique column.
+func (c *CursorPager[_]) orderings() Orderings {
+	if len(c.tieBreaker) == 0 || c.hasUniqueColumn(c.sort) {
+		return c.sort
+	}
+
+	ret := slices.Clone(c.sort)
+	for _, o := range c.tieBreaker {
+		if !slices.ContainsFunc(ret, func(item OrderBy) bool { return item.Column == o.Column }) {
+			ret = append(ret, o)
+		}
+	}
+
+	return ret
+}
+
+// hasUniqueColumn returns true if the orderings contain a declared unique
+// column or the whole tie-breaker key.
+func (c *CursorPager[_]) hasUniqueColumn(orderings Orderings) bool {
+	columns := lo.Map(orderings, func(item OrderBy, _ int) string { return item.Column })
+	if lo.Some(columns, c.uniqueColumns) {
+		return true
+	}
+
+	return len(c.tieBreaker) != 0 && lo.EveryBy(c.tieBreaker, func(item OrderBy) bool {
+		return slices.Contains(columns, item.Column)
+	})
 }
 
 // IsUnlimited returns true if the limit equals NoLimit (unbounded number of records).
@@ -238,6 +545,34 @@ func (c *CursorPager[CursorType]) GetCursor() CursorType {
 	return c.cursor
 }
 
+// GetTokenCodec returns the codec used to encode cursors built for the next pages.
+func (c *CursorPager[CursorType]) GetTokenCodec() *TokenCodec {
+	if c == nil {
+		return nil
+	}
+
+	return c.codec
+}
+
+// GetTokenTTL returns the lifetime of cursors built for the next pages.
+func (c *CursorPager[CursorType]) GetTokenTTL() time.Duration {
+	if c == nil {
+		return 0
+	}
+
+	return c.tokenTTL
+}
+
+// tokenLifetime returns issue and expiration times for a cursor built now.
+func (c *CursorPager[_]) tokenLifetime() (time.Time, time.Time) {
+	issuedAt := c.GetTokenCodec().now().UTC().Truncate(time.Second)
+	if c.GetTokenTTL() <= 0 {
+		return issuedAt, time.Time{}
+	}
+
+	return issuedAt, issuedAt.Add(c.tokenTTL)
+}
+
 // GetDatasetLimit returns the limit adjusted for lookahead:
 //   - if Lookahead = true → GetLimit() + 1
 //   - if Lookahead = false → GetLimit()
@@ -254,18 +589,28 @@ func (c *CursorPager[_]) validate() error {
 	}
 
 	if c.limit == NoLimit && c.lookahead {
-		return fmt.Errorf("cannot apply lookahead to unlimited paging")
+		return ErrLookaheadUnlimited
+	}
+
+	if c.primaryKeys != nil && len(c.tieBreaker) == 0 {
+		return fmt.Errorf("primary key tie-breaker is not resolved: paginate the query first")
 	}
 
-	err := c.sort.validate()
+	sort := c.orderings()
+	err := sort.validate()
 	if err != nil {
 		return err
 	}
 
-	return c.cursor.validate(c.sort)
+	if len(c.uniqueColumns) != 0 && !c.hasUniqueColumn(sort) {
+		return fmt.Errorf("orderings contain no unique column, expected one of %v", c.uniqueColumns)
+	}
+
+	return c.cursor.validate(sort)
 }
 
 // IsLastPage returns true if the result set is the last page in the dataset.
+// It is the opposite of HasNextPage.
 //
 // The last page is determined by one of two conditions:
 //  1. The number of returned records is less than Limit.
@@ -274,23 +619,64 @@ func (c *CursorPager[_]) validate() error {
 // In these cases, return the result set unchanged with an empty token to
 // signal the end of the dataset to the client.
 func IsLastPage[CursorType Cursor, T any](initialPager *CursorPager[CursorType], resultSet []T) bool {
+	return !HasNextPage(initialPager, resultSet)
+}
+
+// HasNextPage returns true if there are records after the fetched page.
+//
+// For a page fetched backwards it is always true: the page was reached from
+// the following one.
+func HasNextPage[CursorType Cursor, T any](initialPager *CursorPager[CursorType], resultSet []T) bool {
+	if initialPager.GetCursor().isBackward() {
+		return true
+	}
+
+	return !isFetchExhausted(initialPager, resultSet)
+}
+
+// HasPrevPage returns true if there are records before the fetched page.
+//
+// For a page fetched forwards it is true if the page does not start at the
+// beginning of the dataset, i.e. the cursor has a start position.
+func HasPrevPage[CursorType Cursor, T any](initialPager *CursorPager[CursorType], resultSet []T) bool {
+	cursor := initialPager.GetCursor()
+	if !cursor.isBackward() {
+		return !cursor.isStart()
+	}
+
+	return !isFetchExhausted(initialPager, resultSet)
+}
+
+// isFetchExhausted returns true if there are no more records in the fetch
+// direction:
+//  1. The number of returned records is less than Limit.
+//  2. Lookahead = true and the number of returned records is less than or equal to Limit.
+func isFetchExhausted[CursorType Cursor, T any](initialPager *CursorPager[CursorType], resultSet []T) bool {
 	return len(resultSet) < initialPager.limit ||
 		(initialPager.lookahead && len(resultSet) <= initialPager.limit)
 }
 
 // TrimResultSet trims the result set to what should be returned to the client.
 //
-// If lookahead = true, drop the last element before returning. Suppose
-// resultSet = [a, b, c].
+// If lookahead = true and the result set contains the lookahead element, drop
+// it before returning. Suppose limit = 2 and resultSet = [a, b, c].
 //
 //   - With lookahead → resultSet becomes [a, b].
 //   - Without lookahead → resultSet remains unchanged.
 //
 // This enables building pagination based on a STRICT comparison with the
 // last element of the result set.
+//
+// If the page was fetched backwards, the result set is also reversed back into
+// the requested order. The input slice is not modified.
 func TrimResultSet[CursorType Cursor, T any](initialPager *CursorPager[CursorType], resultSet []T) []T {
-	if initialPager.lookahead {
-		resultSet = resultSet[:len(resultSet)-1]
+	if initialPager.lookahead && len(resultSet) > initialPager.limit {
+		resultSet = resultSet[:initialPager.limit]
+	}
+
+	if initialPager.GetCursor().isBackward() {
+		resultSet = slices.Clone(resultSet)
+		slices.Reverse(resultSet)
 	}
 
 	return resultSet
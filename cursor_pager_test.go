@@ -4,9 +4,11 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"gorm.io/gorm"
+	"sync"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -42,6 +44,37 @@ func Test_CursorPager_WithMethods_And_SortDedup(t *testing.T) {
 	)
 }
 
+func Test_CursorPager_WithZeroLimit(t *testing.T) {
+	db := newCountTestDB(t)
+	getters := Getters[testCountUser]{"id": func(u testCountUser) any { return u.ID }}
+	pager := NewCursorPager[*DefaultCursor]().WithLimit(5).WithZeroLimit().WithLookahead().
+		WithSort(OrderBy{Column: "id", Direction: DirectionASC})
+	require.Equal(t, 0, pager.GetLimit())
+
+	paged, err := pager.Paginate(db.Model(&testCountUser{}))
+	require.NoError(t, err)
+
+	var users []testCountUser
+	require.NoError(t, paged.Find(&users).Error)
+	require.Len(t, users, 1)
+
+	page, next, err := NextPageCursor(pager, users, getters)
+	require.NoError(t, err)
+	require.Empty(t, page)
+	require.Nil(t, next)
+	require.True(t, HasNextPage(pager, users))
+
+	// Without lookahead nothing is fetched: FETCH NEXT 0 ROWS is not rendered.
+	paged, err = NewCursorPager[*DefaultCursor]().WithZeroLimit().
+		WithSort(OrderBy{Column: "id", Direction: DirectionASC}).
+		Paginate(db.Model(&testCountUser{}))
+	require.NoError(t, err)
+	stmt := paged.Session(&gorm.Session{DryRun: true}).Find(&users).Statement
+	require.Contains(t, stmt.SQL.String(), "1 = 0")
+	require.NoError(t, paged.Find(&users).Error)
+	require.Empty(t, users)
+}
+
 func Test_CursorPager_validate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -189,7 +222,7 @@ func Test_CursorPager_Paginate_PseudoCursor(t *testing.T) {
 			limit:         3,
 			cursor:        &PseudoCursor{offset: 5},
 			lookahead:     false,
-			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = ['\"]lol['\"] ORDER BY id ASC LIMIT 3 OFFSET 5$",
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = ['\"]lol['\"] ORDER BY [`\"]id[`\"] ASC LIMIT 3 OFFSET 5$",
 			expectedArgs:  nil,
 			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"),
 		},
@@ -198,7 +231,7 @@ func Test_CursorPager_Paginate_PseudoCursor(t *testing.T) {
 			limit:         3,
 			cursor:        &PseudoCursor{offset: 5},
 			lookahead:     true,
-			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] ORDER BY id ASC LIMIT 4 OFFSET 5$",
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] ORDER BY [`\"]id[`\"] ASC LIMIT 4 OFFSET 5$",
 			expectedArgs:  nil,
 			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"),
 		},
@@ -207,7 +240,7 @@ func Test_CursorPager_Paginate_PseudoCursor(t *testing.T) {
 			limit:         5,
 			cursor:        &PseudoCursor{offset: 0},
 			lookahead:     false,
-			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] ORDER BY id ASC LIMIT 5$",
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] ORDER BY [`\"]id[`\"] ASC LIMIT 5$",
 			expectedArgs:  nil,
 			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"),
 		},
@@ -216,7 +249,7 @@ func Test_CursorPager_Paginate_PseudoCursor(t *testing.T) {
 			limit:         10,
 			cursor:        nil,
 			lookahead:     false,
-			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] ORDER BY id ASC LIMIT 10$",
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] ORDER BY [`\"]id[`\"] ASC LIMIT 10$",
 			expectedArgs:  nil,
 			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"),
 		},
@@ -290,7 +323,7 @@ func Test_CursorPager_Paginate_DefaultCursor(t *testing.T) {
 			cursor:        &DefaultCursor{elements: []CursorElement{{Column: "id", Value: 5, Operator: OperatorGT}}},
 			orderings:     Orderings([]OrderBy{{Column: "id", Direction: DirectionASC}}),
 			lookahead:     false,
-			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] AND id > (?:\\$\\d|\\?) ORDER BY id ASC LIMIT 3$",
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] AND [`\"]id[`\"] > (?:\\$\\d|\\?) ORDER BY [`\"]id[`\"] ASC LIMIT 3$",
 			expectedArgs:  []driver.Value{5},
 			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(6, "John Doe"),
 		},
@@ -300,7 +333,7 @@ func Test_CursorPager_Paginate_DefaultCursor(t *testing.T) {
 			cursor:        &DefaultCursor{elements: []CursorElement{{Column: "id", Value: 5, Operator: OperatorGT}}},
 			orderings:     Orderings([]OrderBy{{Column: "id", Direction: DirectionASC}}),
 			lookahead:     true,
-			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] AND id > (?:\\$\\d|\\?) ORDER BY id ASC LIMIT 4$",
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] AND [`\"]id[`\"] > (?:\\$\\d|\\?) ORDER BY [`\"]id[`\"] ASC LIMIT 4$",
 			expectedArgs:  []driver.Value{5},
 			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(6, "John Doe"),
 		},
@@ -318,7 +351,7 @@ func Test_CursorPager_Paginate_DefaultCursor(t *testing.T) {
 				{Column: "created_at", Direction: DirectionASC},
 			}),
 			lookahead:     false,
-			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] AND \\(id > (?:\\$\\d|\\?) OR \\(id = (?:\\$\\d|\\?) AND created_at > (?:\\$\\d|\\?)\\)\\) ORDER BY id ASC, created_at ASC LIMIT 5$",
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] AND \\([`\"]id[`\"] > (?:\\$\\d|\\?) OR \\([`\"]id[`\"] = (?:\\$\\d|\\?) AND [`\"]created_at[`\"] > (?:\\$\\d|\\?)\\)\\) ORDER BY [`\"]id[`\"] ASC, [`\"]created_at[`\"] ASC LIMIT 5$",
 			expectedArgs:  []driver.Value{10, 10, "2023-01-01"},
 			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(11, "Jane Doe"),
 		},
@@ -330,7 +363,7 @@ func Test_CursorPager_Paginate_DefaultCursor(t *testing.T) {
 				{Column: "id", Direction: DirectionASC},
 			}),
 			lookahead:     false,
-			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] ORDER BY id ASC LIMIT 10$",
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] ORDER BY [`\"]id[`\"] ASC LIMIT 10$",
 			expectedArgs:  nil,
 			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"),
 		},
@@ -342,7 +375,7 @@ func Test_CursorPager_Paginate_DefaultCursor(t *testing.T) {
 				{Column: "id", Direction: DirectionASC},
 			}),
 			lookahead:     false,
-			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] ORDER BY id ASC LIMIT 10$",
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] ORDER BY [`\"]id[`\"] ASC LIMIT 10$",
 			expectedArgs:  nil,
 			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John Doe"),
 		},
@@ -354,10 +387,26 @@ func Test_CursorPager_Paginate_DefaultCursor(t *testing.T) {
 				{Column: "id", Direction: DirectionDESC},
 			}),
 			lookahead:     false,
-			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] AND id < (?:\\$\\d|\\?) ORDER BY id DESC LIMIT 3$",
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] AND [`\"]id[`\"] < (?:\\$\\d|\\?) ORDER BY [`\"]id[`\"] DESC LIMIT 3$",
 			expectedArgs:  []driver.Value{5},
 			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(4, "Jane Doe"),
 		},
+		{
+			name:  "backward pagination inverts ordering",
+			limit: 3,
+			cursor: NewDefaultCursor(
+				CursorElement{Column: "id", Value: 5, Operator: OperatorLT},
+				CursorElement{Column: "created_at", Value: "2023-01-01", Operator: OperatorGT},
+			).WithBackward(true),
+			orderings: Orderings([]OrderBy{
+				{Column: "id", Direction: DirectionASC},
+				{Column: "created_at", Direction: DirectionDESC},
+			}),
+			lookahead:     true,
+			expectedQuery: "^SELECT \\* FROM [`'\"]users[`'\"] WHERE name = [`'\"]lol[`'\"] AND \\([`\"]id[`\"] < (?:\\$\\d|\\?) OR \\([`\"]id[`\"] = (?:\\$\\d|\\?) AND [`\"]created_at[`\"] > (?:\\$\\d|\\?)\\)\\) ORDER BY [`\"]id[`\"] DESC, [`\"]created_at[`\"] ASC LIMIT 4$",
+			expectedArgs:  []driver.Value{5, 5, "2023-01-01"},
+			expectedRows:  sqlmock.NewRows([]string{"id", "name"}).AddRow(4, "Jane Doe"),
+		},
 	}
 
 	for _, sqlMockFn := range sqlMockFnList {
@@ -383,7 +432,8 @@ func Test_CursorPager_Paginate_DefaultCursor(t *testing.T) {
 					p = p.WithLookahead()
 				}
 
-				paged, err := p.Paginate(db.Select("*").Table("users").Where("name = 'lol'"))
+				// The primary key of the model holds no NULL values.
+				paged, err := p.Paginate(db.Model(&tUser{}).Select("*").Table("users").Where("name = 'lol'"))
 				if err != nil {
 					t.Fatalf("paginate: %v", err)
 				}
@@ -398,3 +448,381 @@ func Test_CursorPager_Paginate_DefaultCursor(t *testing.T) {
 		}
 	}
 }
+
+// tRenamedDialector reports a different dialect name to test dialect specific rendering.
+type tRenamedDialector struct {
+	gorm.Dialector
+	name string
+}
+
+func (d tRenamedDialector) Name() string {
+	return d.name
+}
+
+func Test_CursorPager_Paginate_RowValueComparison(t *testing.T) {
+	_, mysqlDB, _, err := newGORMMySQLMock()
+	require.NoError(t, err)
+	_, postgresDB, _, err := newGORMPostgresMock()
+	require.NoError(t, err)
+	sqlServerConfig := *postgresDB.Config
+	sqlServerConfig.Dialector = tRenamedDialector{postgresDB.Dialector, "sqlserver"}
+	sqlServerDB := postgresDB.Session(&gorm.Session{})
+	sqlServerDB.Config = &sqlServerConfig
+
+	sameDirection := Orderings{
+		{Column: "id", Direction: DirectionASC},
+		{Column: "created_at", Direction: DirectionASC},
+	}
+	mixedDirection := Orderings{
+		{Column: "id", Direction: DirectionASC},
+		{Column: "created_at", Direction: DirectionDESC},
+	}
+
+	tests := []struct {
+		name      string
+		db        *gorm.DB
+		cursor    *DefaultCursor
+		orderings Orderings
+		expected  string
+	}{
+		{
+			name: "postgres same direction",
+			db:   postgresDB,
+			cursor: NewDefaultCursor(
+				CursorElement{Column: "id", Value: 10, Operator: OperatorGT},
+				CursorElement{Column: "created_at", Value: "2023-01-01", Operator: OperatorGT},
+			),
+			orderings: sameDirection,
+			expected:  `SELECT * FROM "users" WHERE ("id", "created_at") > ($1, $2) ORDER BY "id" ASC, "created_at" ASC LIMIT 5`,
+		},
+		{
+			name: "mysql same direction",
+			db:   mysqlDB,
+			cursor: NewDefaultCursor(
+				CursorElement{Column: "id", Value: 10, Operator: OperatorGT},
+				CursorElement{Column: "created_at", Value: "2023-01-01", Operator: OperatorGT},
+			),
+			orderings: sameDirection,
+			expected:  "SELECT * FROM `users` WHERE (`id`, `created_at`) > (?, ?) ORDER BY `id` ASC, `created_at` ASC LIMIT 5",
+		},
+		{
+			name: "postgres backward",
+			db:   postgresDB,
+			cursor: NewDefaultCursor(
+				CursorElement{Column: "id", Value: 10, Operator: OperatorLT},
+				CursorElement{Column: "created_at", Value: "2023-01-01", Operator: OperatorLT},
+			).WithBackward(true),
+			orderings: sameDirection,
+			expected:  `SELECT * FROM "users" WHERE ("id", "created_at") < ($1, $2) ORDER BY "id" DESC, "created_at" DESC LIMIT 5`,
+		},
+		{
+			name: "postgres mixed directions",
+			db:   postgresDB,
+			cursor: NewDefaultCursor(
+				CursorElement{Column: "id", Value: 10, Operator: OperatorGT},
+				CursorElement{Column: "created_at", Value: "2023-01-01", Operator: OperatorLT},
+			),
+			orderings: mixedDirection,
+			expected:  `SELECT * FROM "users" WHERE ("id" > $1 OR ("id" = $2 AND "created_at" < $3)) ORDER BY "id" ASC, "created_at" DESC LIMIT 5`,
+		},
+		{
+			name: "mysql mixed directions",
+			db:   mysqlDB,
+			cursor: NewDefaultCursor(
+				CursorElement{Column: "id", Value: 10, Operator: OperatorGT},
+				CursorElement{Column: "created_at", Value: "2023-01-01", Operator: OperatorLT},
+			),
+			orderings: mixedDirection,
+			expected:  "SELECT * FROM `users` WHERE (`id` > ? OR (`id` = ? AND `created_at` < ?)) ORDER BY `id` ASC, `created_at` DESC LIMIT 5",
+		},
+		{
+			name: "unsupported dialect",
+			db:   sqlServerDB,
+			cursor: NewDefaultCursor(
+				CursorElement{Column: "id", Value: 10, Operator: OperatorGT},
+				CursorElement{Column: "created_at", Value: "2023-01-01", Operator: OperatorGT},
+			),
+			orderings: sameDirection,
+			expected:  `SELECT * FROM "users" WHERE ([id] > $1 OR ([id] = $2 AND [created_at] > $3)) ORDER BY [id] ASC, [created_at] ASC LIMIT 5`,
+		},
+		{
+			name:      "single column",
+			db:        postgresDB,
+			cursor:    NewDefaultCursor(CursorElement{Column: "id", Value: 10, Operator: OperatorGT}),
+			orderings: Orderings{{Column: "id", Direction: DirectionASC}},
+			expected:  `SELECT * FROM "users" WHERE "id" > $1 ORDER BY "id" ASC LIMIT 5`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewCursorPager[*DefaultCursor]().
+				WithLimit(5).
+				WithCursor(tt.cursor).
+				WithSort(tt.orderings...).
+				WithNotNullColumns("id").
+				WithRowValueComparison()
+
+			paged, err := p.Paginate(tt.db.Session(&gorm.Session{DryRun: true}).Table("users"))
+			require.NoError(t, err)
+
+			stmt := paged.Find(&[]map[string]any{}).Statement
+			require.Equal(t, tt.expected, stmt.SQL.String())
+		})
+	}
+
+	t.Run("nullable column", func(t *testing.T) {
+		p := NewCursorPager[*DefaultCursor]().
+			WithLimit(5).
+			WithCursor(NewDefaultCursor(
+				CursorElement{Column: "id", Value: 10, Operator: OperatorGT},
+				CursorElement{Column: "created_at", Value: "2023-01-01", Operator: OperatorGT},
+			)).
+			WithSort(sameDirection...).
+			WithRowValueComparison()
+
+		paged, err := p.Paginate(postgresDB.Session(&gorm.Session{DryRun: true}).Table("users"))
+		require.NoError(t, err)
+
+		// NULL values are the largest in PostgreSQL: rows with NULL "id" follow the cursor.
+		stmt := paged.Find(&[]map[string]any{}).Statement
+		require.Equal(t,
+			`SELECT * FROM "users" WHERE ("id" > $1 OR "id" IS NULL OR ("id" = $2 AND "created_at" > $3)) ORDER BY "id" ASC, "created_at" ASC LIMIT 5`,
+			stmt.SQL.String(),
+		)
+	})
+}
+
+func Test_CursorPager_Paginate_Collation_SQLite(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+
+	db, err := newGORMSQLite()
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&user{}))
+
+	names := []string{"bob", "Alice", "carol", "Bob", "alice", "Dave", "BOB", "eve", "Carol", "dave"}
+	for i, name := range names {
+		require.NoError(t, db.Create(&user{ID: i + 1, Name: name}).Error)
+	}
+
+	sort := Orderings{
+		{Column: "name", Direction: DirectionASC, Collation: "NOCASE"},
+		{Column: "id", Direction: DirectionASC},
+	}
+	getters := Getters[user]{
+		"name": func(u user) any { return u.Name },
+		"id":   func(u user) any { return u.ID },
+	}
+
+	var expected []int
+	require.NoError(t, db.Model(&user{}).Order("name COLLATE NOCASE ASC, id ASC").Pluck("id", &expected).Error)
+
+	for _, limit := range []int{1, 2, 3, 4} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			var (
+				got    []int
+				cursor *DefaultCursor
+			)
+			for range names {
+				pager := NewCursorPager[*DefaultCursor]().WithLimit(limit).WithLookahead().WithSort(sort...).WithCursor(cursor)
+				paged, err := pager.Paginate(db.Model(&user{}))
+				require.NoError(t, err)
+
+				var page []user
+				require.NoError(t, paged.Find(&page).Error)
+
+				page, cursor, err = NextPageCursor(pager, page, getters)
+				require.NoError(t, err)
+				for _, u := range page {
+					got = append(got, u.ID)
+				}
+				if cursor == nil {
+					break
+				}
+			}
+
+			require.Equal(t, expected, got)
+		})
+	}
+
+	t.Run("backward", func(t *testing.T) {
+		pager := NewCursorPager[*DefaultCursor]().WithLimit(3).WithSort(sort...).
+			WithCursor(NewDefaultCursor(
+				CursorElement{Column: "name", Value: "carol", Operator: OperatorLT},
+				CursorElement{Column: "id", Value: 3, Operator: OperatorLT},
+			).WithBackward(true))
+		paged, err := pager.Paginate(db.Model(&user{}))
+		require.NoError(t, err)
+
+		var page []user
+		require.NoError(t, paged.Find(&page).Error)
+		page = TrimResultSet(pager, page)
+
+		// The three rows preceding "carol" (id 3) in NOCASE order.
+		require.Equal(t, expected[2:5], lo.Map(page, func(u user, _ int) int { return u.ID }))
+	})
+}
+
+func Test_CursorPager_TieBreaker(t *testing.T) {
+	byName := OrderBy{Column: "name", Direction: DirectionDESC}
+	byID := OrderBy{Column: "id", Direction: DirectionASC}
+
+	tests := []struct {
+		name     string
+		pager    *CursorPager[*DefaultCursor]
+		expected Orderings
+		ok       bool
+	}{
+		{
+			name:     "no enforcement",
+			pager:    NewCursorPager[*DefaultCursor]().WithSort(byName),
+			expected: Orderings{byName},
+			ok:       true,
+		},
+		{
+			name:  "missing unique column",
+			pager: NewCursorPager[*DefaultCursor]().WithSort(byName).WithUniqueColumns("id", "email"),
+			ok:    false,
+		},
+		{
+			name:     "unique column present",
+			pager:    NewCursorPager[*DefaultCursor]().WithSort(byName, OrderBy{Column: "email", Direction: DirectionASC}).WithUniqueColumns("id", "email"),
+			expected: Orderings{byName, {Column: "email", Direction: DirectionASC}},
+			ok:       true,
+		},
+		{
+			name:     "tie-breaker appended",
+			pager:    NewCursorPager[*DefaultCursor]().WithUniqueColumns("id").WithTieBreaker(byID).WithSort(byName),
+			expected: Orderings{byName, byID},
+			ok:       true,
+		},
+		{
+			name:     "tie-breaker present",
+			pager:    NewCursorPager[*DefaultCursor]().WithTieBreaker(byID).WithSort(OrderBy{Column: "id", Direction: DirectionDESC}, byName),
+			expected: Orderings{{Column: "id", Direction: DirectionDESC}, byName},
+			ok:       true,
+		},
+		{
+			name: "composite tie-breaker completed",
+			pager: NewCursorPager[*DefaultCursor]().
+				WithTieBreaker(OrderBy{Column: "tenant_id", Direction: DirectionASC}, byID).
+				WithSort(byID, byName),
+			expected: Orderings{byID, byName, {Column: "tenant_id", Direction: DirectionASC}},
+			ok:       true,
+		},
+		{
+			name:  "primary key not resolved",
+			pager: NewCursorPager[*DefaultCursor]().WithSort(byName).WithPrimaryKeyTieBreaker(),
+			ok:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pager.validate()
+			if !tt.ok {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, tt.pager.GetSort())
+		})
+	}
+}
+
+func Test_CursorPager_PrimaryKeyTieBreaker_SQLite(t *testing.T) {
+	type player struct {
+		ID    int
+		Score int
+	}
+
+	db, err := newGORMSQLite()
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&player{}))
+
+	// Many equal scores: without a tie-breaker pages skip rows.
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, db.Create(&player{ID: i, Score: i % 3}).Error)
+	}
+
+	var expected []int
+	require.NoError(t, db.Model(&player{}).Order("score DESC, id DESC").Pluck("id", &expected).Error)
+
+	sort := OrderBy{Column: "score", Direction: DirectionDESC}
+	getters, err := GettersFromModel[player](db)
+	require.NoError(t, err)
+
+	var (
+		got    []int
+		cursor *DefaultCursor
+	)
+	for range expected {
+		pager := NewCursorPager[*DefaultCursor]().WithLimit(3).WithSort(sort).WithPrimaryKeyTieBreaker().WithCursor(cursor)
+		paged, err := pager.Paginate(db.Model(&player{}))
+		require.NoError(t, err)
+		require.Equal(t, Orderings{sort}, pager.GetSort())
+
+		var page []player
+		require.NoError(t, paged.Find(&page).Error)
+
+		page, cursor, err = NextPageCursor(pager, page, getters)
+		require.NoError(t, err)
+		for _, p := range page {
+			got = append(got, p.ID)
+		}
+		if cursor == nil {
+			break
+		}
+		require.Equal(t, Orderings{sort, {Column: "players.id", Direction: DirectionDESC}}, cursor.GetOrderings())
+	}
+
+	require.Equal(t, expected, got)
+
+	_, err = NewCursorPager[*DefaultCursor]().WithLimit(3).WithSort(sort).WithPrimaryKeyTieBreaker().Paginate(db.Table("players"))
+	require.Error(t, err)
+
+	t.Run("empty sort", func(t *testing.T) {
+		_, err := NewCursorPager[*DefaultCursor]().WithLimit(3).WithPrimaryKeyTieBreaker().Paginate(db.Model(&player{}))
+		require.ErrorIs(t, err, ErrEmptySort)
+	})
+
+	t.Run("reused with another model", func(t *testing.T) {
+		type team struct {
+			Code  string `gorm:"primaryKey"`
+			Score int
+		}
+		require.NoError(t, db.AutoMigrate(&team{}))
+		require.NoError(t, db.Create(&team{Code: "a", Score: 1}).Error)
+
+		pager := NewCursorPager[*DefaultCursor]().WithLimit(1).WithSort(sort).WithPrimaryKeyTieBreaker()
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, err := pager.Paginate(db.Model(&player{}))
+				assert.NoError(t, err)
+			}()
+			go func() {
+				defer wg.Done()
+				_, err := pager.Paginate(db.Model(&team{}))
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, Orderings{sort}, pager.GetSort())
+
+		teamGetters, err := GettersFromModel[team](db)
+		require.NoError(t, err)
+		_, teamCursor, err := NextPageCursor(pager, []team{{Code: "a", Score: 1}, {Code: "b", Score: 1}}, teamGetters)
+		require.NoError(t, err)
+		require.Equal(t, Orderings{sort, {Column: "teams.code", Direction: DirectionDESC}}, teamCursor.GetOrderings())
+
+		_, playerCursor, err := NextPageCursor(pager, []player{{ID: 1, Score: 1}, {ID: 2, Score: 1}}, getters)
+		require.NoError(t, err)
+		require.Equal(t, Orderings{sort, {Column: "players.id", Direction: DirectionDESC}}, playerCursor.GetOrderings())
+	})
+}
@@ -0,0 +1,160 @@
+package gopager
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// UUID is a cursor value representing a UUID. Values of [16]byte based
+// types implementing driver.Valuer or fmt.Stringer (e.g.
+// github.com/google/uuid.UUID) are stored in tokens as UUID and restored as
+// UUID on decode. Other byte arrays, e.g. binary(16) keys, are stored as
+// []byte.
+type UUID [16]byte
+
+// ParseUUID parses a UUID in the canonical form
+// xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func ParseUUID(s string) (UUID, error) {
+	var ret UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return ret, fmt.Errorf("invalid UUID format '%s'", s)
+	}
+
+	_, err := hex.Decode(ret[:], []byte(strings.ReplaceAll(s, "-", "")))
+	if err != nil {
+		return ret, fmt.Errorf("invalid UUID format '%s': %w", s, err)
+	}
+
+	return ret, nil
+}
+
+// String returns the canonical form of the UUID.
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf[:])
+}
+
+// Value - implements driver.Valuer.
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Decimal is a cursor value representing an arbitrary precision decimal number
+// in its text form. Return Decimal from a getter to keep decimal columns exact:
+//
+//	"balance": func(a Account) any { return gopager.Decimal(a.Balance.String()) },
+type Decimal string
+
+// Value - implements driver.Valuer.
+func (d Decimal) Value() (driver.Value, error) {
+	return string(d), nil
+}
+
+var (
+	_ driver.Valuer = UUID{}
+	_ driver.Valuer = Decimal("")
+)
+
+// isNullValue returns true if the cursor value represents NULL, e.g. a nil
+// pointer or an invalid sql.NullString.
+func isNullValue(v any) bool {
+	ret, err := normalizeCursorValue(v)
+	return err == nil && ret == nil
+}
+
+// normalizeCursorValue converts a value returned by a getter into one of the
+// types supported in tokens: nil, bool, int64, uint64, float64, string,
+// []byte, time.Time, UUID or Decimal.
+func normalizeCursorValue(v any) (any, error) {
+	switch vt := v.(type) {
+	case nil, bool, int64, uint64, float64, string, []byte, time.Time, UUID, Decimal:
+		return vt, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+
+		// Prefer the pointed value: it keeps the type of e.g. *uuid.UUID, whose
+		// method set also contains driver.Valuer.
+		ret, err := normalizeCursorValue(rv.Elem().Interface())
+		if err == nil {
+			return ret, nil
+		}
+	}
+
+	if isUUIDType(rv.Type()) {
+		var ret UUID
+		reflect.Copy(reflect.ValueOf(&ret).Elem(), rv)
+		return ret, nil
+	}
+
+	if valuer, ok := v.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+		if _, isValuer := value.(driver.Valuer); isValuer {
+			return nil, fmt.Errorf("unsupported value type %T", v)
+		}
+
+		return normalizeCursorValue(value)
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Bytes(), nil
+		}
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			ret := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(ret), rv)
+			return ret, nil
+		}
+	default:
+	}
+
+	return nil, fmt.Errorf("unsupported value type %T", v)
+}
+
+// isUUIDType returns true if t is a [16]byte based type bound as a UUID: it
+// implements driver.Valuer or fmt.Stringer.
+func isUUIDType(t reflect.Type) bool {
+	if t == nil || t.Kind() != reflect.Array || t.Len() != len(UUID{}) || t.Elem().Kind() != reflect.Uint8 {
+		return false
+	}
+
+	return t.Implements(_valuerType) || t.Implements(_stringerType)
+}
+
+var (
+	_valuerType   = reflect.TypeFor[driver.Valuer]()
+	_stringerType = reflect.TypeFor[fmt.Stringer]()
+)
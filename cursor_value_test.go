@@ -0,0 +1,124 @@
+package gopager
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UUID(t *testing.T) {
+	const canonical = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	u, err := ParseUUID(canonical)
+	require.NoError(t, err)
+	require.Equal(t, canonical, u.String())
+
+	value, err := u.Value()
+	require.NoError(t, err)
+	require.Equal(t, canonical, value)
+
+	for _, invalid := range []string{"", "6ba7b8109dad11d180b400c04fd430c8", "6ba7b810-9dad-11d1-80b4-00c04fd430cz"} {
+		_, err = ParseUUID(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+// testUUID is a UUID type of a third party library.
+type testUUID [16]byte
+
+func (u testUUID) String() string {
+	return UUID(u).String()
+}
+
+func Test_normalizeCursorValue(t *testing.T) {
+	type id int32
+	type rawBinary [16]byte
+
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	i := 5
+
+	tests := []struct {
+		name    string
+		in      any
+		want    any
+		wantErr bool
+	}{
+		{"nil", nil, nil, false},
+		{"int", 5, int64(5), false},
+		{"named int", id(7), int64(7), false},
+		{"uint8", uint8(7), uint64(7), false},
+		{"float32", float32(0.5), 0.5, false},
+		{"pointer", &i, int64(5), false},
+		{"nil pointer", (*int)(nil), nil, false},
+		{"time", ts, ts, false},
+		{"time pointer", &ts, ts, false},
+		{"uuid based type", testUUID{1}, UUID{1}, false},
+		{"uuid based type pointer", &testUUID{1}, UUID{1}, false},
+		{"byte array", [16]byte{1}, append([]byte{1}, make([]byte, 15)...), false},
+		{"byte array based type", rawBinary{1}, append([]byte{1}, make([]byte, 15)...), false},
+		{"short byte array", [2]byte{1, 2}, []byte{1, 2}, false},
+		{"valuer", sql.NullString{String: "a", Valid: true}, "a", false},
+		{"null valuer", sql.NullInt64{}, nil, false},
+		{"decimal", Decimal("1.5"), Decimal("1.5"), false},
+		{"unsupported", struct{}{}, nil, true},
+		{"unsupported slice", []int{1}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeCursorValue(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_ByteArrayCursorValue_SQLite(t *testing.T) {
+	type key struct {
+		ID   []byte `gorm:"primaryKey"`
+		Name string
+	}
+
+	db, err := newGORMSQLite()
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&key{}))
+	for i := byte(1); i <= 5; i++ {
+		id := [16]byte{15: i}
+		require.NoError(t, db.Create(&key{ID: id[:], Name: string('a' + i)}).Error)
+	}
+
+	// A binary(16) key is restored from tokens as bytes, not as a UUID string.
+	getters := Getters[key]{"id": func(k key) any { return [16]byte(k.ID) }}
+	sort := OrderBy{Column: "id", Direction: DirectionASC}
+
+	var (
+		names []string
+		token string
+	)
+	for range 5 {
+		pager, err := DecodeCursorPager(2, token, sort)
+		require.NoError(t, err)
+
+		paged, err := pager.WithLookahead().Paginate(db.Model(&key{}))
+		require.NoError(t, err)
+		var page []key
+		require.NoError(t, paged.Find(&page).Error)
+
+		page, next, err := NextPageCursor(pager, page, getters)
+		require.NoError(t, err)
+		for _, k := range page {
+			names = append(names, k.Name)
+		}
+		if next == nil {
+			break
+		}
+		token = next.String()
+	}
+	require.Equal(t, []string{"b", "c", "d", "e", "f"}, names)
+}
@@ -0,0 +1,360 @@
+package gopager
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// Dialect renders SQL fragments for a specific database. Use it to embed
+// pagination into raw queries, e.g. executed with pgx or database/sql.
+type Dialect interface {
+	// Name returns the dialect name as reported by gorm.Dialector.Name().
+	Name() string
+	// Placeholder returns the placeholder of the n-th query argument,
+	// counting from 1.
+	Placeholder(n int) string
+	// True returns an always true condition.
+	True() string
+	// QuoteIdentifier quotes a single part of an identifier, e.g. a table or
+	// a column name, escaping quote characters inside it.
+	QuoteIdentifier(name string) string
+	// SupportsRowValues reports whether row value comparisons such as
+	// (a, b) > (1, 2) are supported.
+	SupportsRowValues() bool
+	// NullsLargest reports whether NULL values are sorted as larger than any
+	// other value by default.
+	NullsLargest() bool
+	// SupportsNullsOrdering reports whether NULLS FIRST|LAST is supported in
+	// ORDER BY. Otherwise the placement is emulated with an extra sort key.
+	SupportsNullsOrdering() bool
+	// Collation renders the name of a collation for the COLLATE clause.
+	// Returns an error if the name cannot be used with the dialect.
+	Collation(name string) (string, error)
+	// LimitOffset renders the clause limiting the result set. Limit equal to
+	// NoLimit means no limit. Returns an empty string if there is nothing to
+	// limit. FETCH NEXT 0 ROWS is rejected by SQL Server, so dialects with
+	// OFFSET ... FETCH render a zero limit as an offset skipping any dataset:
+	// CursorPager.ToSQL adds a false condition too, so the rows are never read.
+	LimitOffset(limit int, offset int) string
+}
+
+var (
+	// DialectPostgres renders SQL for PostgreSQL: $1 placeholders, LIMIT/OFFSET.
+	DialectPostgres Dialect = sqlDialect{
+		name:        "postgres",
+		placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		trueLiteral: "TRUE",
+		quotes:      [2]byte{'"', '"'},
+		rowValues:   true,
+		nulls:       true,
+		nullsLarge:  true,
+		// Collation names are identifiers, e.g. "C" or "en-US-x-icu".
+		quoteCollation: true,
+	}
+	// DialectMySQL renders SQL for MySQL: ? placeholders, LIMIT/OFFSET.
+	DialectMySQL Dialect = sqlDialect{
+		name:        "mysql",
+		placeholder: func(int) string { return "?" },
+		trueLiteral: "TRUE",
+		quotes:      [2]byte{'`', '`'},
+		rowValues:   true,
+		nullsKey:    "%s IS NULL",
+		// MySQL does not support OFFSET without LIMIT.
+		unlimited: "18446744073709551615",
+	}
+	// DialectSQLite renders SQL for SQLite: ? placeholders, LIMIT/OFFSET.
+	DialectSQLite Dialect = sqlDialect{
+		name:        "sqlite",
+		placeholder: func(int) string { return "?" },
+		trueLiteral: "TRUE",
+		quotes:      [2]byte{'"', '"'},
+		rowValues:   true,
+		// NULLS FIRST|LAST is supported since SQLite 3.30.
+		nulls: true,
+		// SQLite does not support OFFSET without LIMIT.
+		unlimited: "-1",
+	}
+	// DialectSQLServer renders SQL for SQL Server 2012+: @p1 placeholders,
+	// OFFSET ... ROWS FETCH NEXT ... ROWS ONLY.
+	DialectSQLServer Dialect = sqlDialect{
+		name:        "sqlserver",
+		placeholder: func(n int) string { return "@p" + strconv.Itoa(n) },
+		trueLiteral: "1 = 1",
+		quotes:      [2]byte{'[', ']'},
+		fetch:       true,
+	}
+	// DialectOracle renders SQL for Oracle 12c+: :1 placeholders,
+	// OFFSET ... ROWS FETCH NEXT ... ROWS ONLY.
+	DialectOracle Dialect = sqlDialect{
+		name:        "oracle",
+		placeholder: func(n int) string { return ":" + strconv.Itoa(n) },
+		trueLiteral: "1 = 1",
+		quotes:      [2]byte{'"', '"'},
+		fetch:       true,
+		nulls:       true,
+		nullsLarge:  true,
+	}
+)
+
+// _dialects are the built-in dialects by name.
+var _dialects = map[string]Dialect{
+	DialectPostgres.Name():  DialectPostgres,
+	DialectMySQL.Name():     DialectMySQL,
+	DialectSQLite.Name():    DialectSQLite,
+	DialectSQLServer.Name(): DialectSQLServer,
+	DialectOracle.Name():    DialectOracle,
+}
+
+// DialectByName returns the built-in dialect with the name as reported by
+// gorm.Dialector.Name().
+func DialectByName(name string) (Dialect, bool) {
+	ret, ok := _dialects[name]
+	return ret, ok
+}
+
+// dialectOf returns the built-in dialect of db.
+func dialectOf(db *gorm.DB) (Dialect, bool) {
+	if db == nil || db.Dialector == nil {
+		return nil, false
+	}
+
+	return DialectByName(db.Dialector.Name())
+}
+
+// gormDialectOf returns the dialect quoting identifiers in expressions passed
+// to db. Identifiers of dialects unknown to gopager are quoted by the gorm
+// dialector.
+func gormDialectOf(db *gorm.DB) Dialect {
+	if dialect, ok := dialectOf(db); ok {
+		return dialect
+	}
+	if db == nil || db.Dialector == nil {
+		return _questionMarkDialect
+	}
+
+	return gormDialect{plainDialect: _questionMarkDialect, dialector: db.Dialector}
+}
+
+// sqlDialect is a built-in Dialect.
+type sqlDialect struct {
+	name        string
+	placeholder func(n int) string
+	trueLiteral string
+	// quotes are the opening and the closing identifier quote characters.
+	quotes    [2]byte
+	rowValues bool
+	// nulls enables NULLS FIRST|LAST in ORDER BY.
+	nulls bool
+	// nullsLarge sorts NULL values as the largest ones.
+	nullsLarge bool
+	// nullsKey is the format of the sort key emulating the placement of NULL
+	// values. Empty means the portable CASE expression.
+	nullsKey string
+	// quoteCollation quotes collation names as identifiers. Otherwise only
+	// bare names are accepted.
+	quoteCollation bool
+	// fetch renders OFFSET ... ROWS FETCH NEXT ... ROWS ONLY instead of
+	// LIMIT ... OFFSET ....
+	fetch bool
+	// unlimited is the limit rendered when only the offset is set. Empty
+	// means LIMIT can be omitted.
+	unlimited string
+}
+
+// Name - implements Dialect.
+func (d sqlDialect) Name() string {
+	return d.name
+}
+
+// Placeholder - implements Dialect.
+func (d sqlDialect) Placeholder(n int) string {
+	return d.placeholder(n)
+}
+
+// True - implements Dialect.
+func (d sqlDialect) True() string {
+	return d.trueLiteral
+}
+
+// QuoteIdentifier - implements Dialect.
+func (d sqlDialect) QuoteIdentifier(name string) string {
+	closing := string(d.quotes[1])
+	return string(d.quotes[0]) + strings.ReplaceAll(name, closing, closing+closing) + closing
+}
+
+// SupportsRowValues - implements Dialect.
+func (d sqlDialect) SupportsRowValues() bool {
+	return d.rowValues
+}
+
+// NullsLargest - implements Dialect.
+func (d sqlDialect) NullsLargest() bool {
+	return d.nullsLarge
+}
+
+// SupportsNullsOrdering - implements Dialect.
+func (d sqlDialect) SupportsNullsOrdering() bool {
+	return d.nulls
+}
+
+// Collation - implements Dialect.
+func (d sqlDialect) Collation(name string) (string, error) {
+	if d.quoteCollation {
+		return d.QuoteIdentifier(name), nil
+	}
+	if !isBareIdentifier(name) {
+		return "", fmt.Errorf("collation '%s' is not supported by %s", name, d.name)
+	}
+
+	return name, nil
+}
+
+// LimitOffset - implements Dialect.
+func (d sqlDialect) LimitOffset(limit int, offset int) string {
+	if d.fetch {
+		switch {
+		case limit == NoLimit && offset == 0:
+			return ""
+		case limit == NoLimit:
+			return fmt.Sprintf("OFFSET %d ROWS", offset)
+		case limit == 0:
+			return fmt.Sprintf("OFFSET %d ROWS", math.MaxInt64)
+		default:
+			return fmt.Sprintf("OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, limit)
+		}
+	}
+
+	switch {
+	case limit == NoLimit && offset == 0:
+		return ""
+	case limit == NoLimit && d.unlimited == "":
+		return fmt.Sprintf("OFFSET %d", offset)
+	case limit == NoLimit:
+		return fmt.Sprintf("LIMIT %s OFFSET %d", d.unlimited, offset)
+	case offset == 0:
+		return fmt.Sprintf("LIMIT %d", limit)
+	default:
+		return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+	}
+}
+
+// plainDialect renders ? placeholders and identifiers as they are given.
+type plainDialect struct {
+	sqlDialect
+}
+
+// QuoteIdentifier - implements Dialect. Returns the name as is.
+func (d plainDialect) QuoteIdentifier(name string) string {
+	return name
+}
+
+// Collation - implements Dialect. Returns the name as is.
+func (d plainDialect) Collation(name string) (string, error) {
+	return name, nil
+}
+
+// _questionMarkDialect is used by the dialect agnostic ToSQL methods.
+var _questionMarkDialect = plainDialect{sqlDialect{
+	placeholder: func(int) string { return "?" },
+	trueLiteral: "TRUE",
+	nulls:       true,
+	nullsLarge:  true,
+}}
+
+// nullsSortKey returns the sort key which is 1 for NULL values of the quoted
+// column and 0 otherwise.
+func nullsSortKey(dialect Dialect, column string) string {
+	if d, ok := dialect.(sqlDialect); ok && d.nullsKey != "" {
+		return fmt.Sprintf(d.nullsKey, column)
+	}
+
+	return fmt.Sprintf("CASE WHEN %s IS NULL THEN 1 ELSE 0 END", column)
+}
+
+// gormDialect quotes identifiers with a gorm dialector unknown to gopager.
+type gormDialect struct {
+	plainDialect
+	dialector gorm.Dialector
+}
+
+// Name - implements Dialect.
+func (d gormDialect) Name() string {
+	return d.dialector.Name()
+}
+
+// QuoteIdentifier - implements Dialect. Dialectors split names on dots, so
+// only the quote characters are taken from the dialector: the part is quoted
+// as a whole, a closing quote inside it is doubled.
+func (d gormDialect) QuoteIdentifier(name string) string {
+	var b strings.Builder
+	d.dialector.QuoteTo(&b, "x")
+	probe := b.String()
+	if len(probe) != 3 || probe[1] != 'x' {
+		// The dialector does not quote identifiers.
+		return name
+	}
+
+	opening, closing := probe[:1], probe[2:]
+
+	return opening + strings.ReplaceAll(name, closing, closing+closing) + closing
+}
+
+// sqlArgs collects query arguments and numbers their placeholders.
+type sqlArgs struct {
+	dialect Dialect
+	// bindVars renders ? placeholders bound later by gorm.
+	bindVars bool
+	// next is the number of the next argument.
+	next   int
+	values []driver.Value
+}
+
+func newSQLArgs(dialect Dialect, argIndex int) *sqlArgs {
+	return &sqlArgs{dialect: dialect, next: argIndex}
+}
+
+// newGORMArgs returns arguments of an expression passed to gorm. Identifiers
+// are quoted for the dialect.
+func newGORMArgs(dialect Dialect) *sqlArgs {
+	return &sqlArgs{dialect: dialect, bindVars: true, next: 1}
+}
+
+// vars returns the values as gorm expression variables.
+func (a *sqlArgs) vars() []any {
+	return lo.Map(a.values, func(item driver.Value, _ int) any { return item })
+}
+
+// add appends an argument and returns its placeholder.
+func (a *sqlArgs) add(value driver.Value) string {
+	ret := "?"
+	if !a.bindVars {
+		ret = a.dialect.Placeholder(a.next)
+	}
+	a.next++
+	a.values = append(a.values, value)
+
+	return ret
+}
+
+// SQLFragment is the pagination part of a raw query rendered for a Dialect.
+//
+// Usage:
+//
+//	query := fmt.Sprintf("SELECT * FROM users WHERE %s ORDER BY %s %s", f.Where, f.OrderBy, f.LimitOffset)
+type SQLFragment struct {
+	// Where is the keyset condition. Always true if the cursor is empty.
+	Where string
+	// OrderBy is the list of orderings without the ORDER BY keyword.
+	OrderBy string
+	// LimitOffset limits the result set, e.g. "LIMIT 10 OFFSET 20" or
+	// "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY". Empty if there is nothing to limit.
+	LimitOffset string
+	// Args are the values of the placeholders in Where.
+	Args []driver.Value
+}
@@ -0,0 +1,270 @@
+package gopager
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func Test_Dialect_LimitOffset(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		limit    int
+		offset   int
+		expected string
+	}{
+		{DialectPostgres, 10, 0, "LIMIT 10"},
+		{DialectPostgres, 10, 20, "LIMIT 10 OFFSET 20"},
+		{DialectPostgres, NoLimit, 20, "OFFSET 20"},
+		{DialectPostgres, NoLimit, 0, ""},
+		{DialectMySQL, 10, 20, "LIMIT 10 OFFSET 20"},
+		{DialectMySQL, NoLimit, 20, "LIMIT 18446744073709551615 OFFSET 20"},
+		{DialectSQLite, NoLimit, 20, "LIMIT -1 OFFSET 20"},
+		{DialectSQLServer, 10, 0, "OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{DialectSQLServer, 10, 20, "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{DialectSQLServer, NoLimit, 20, "OFFSET 20 ROWS"},
+		{DialectSQLServer, 0, 20, "OFFSET 9223372036854775807 ROWS"},
+		{DialectPostgres, 0, 20, "LIMIT 0 OFFSET 20"},
+		{DialectOracle, 10, 20, "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{DialectOracle, NoLimit, 0, ""},
+		{DialectOracle, 0, 0, "OFFSET 9223372036854775807 ROWS"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, tt.dialect.LimitOffset(tt.limit, tt.offset), "%s %d %d", tt.dialect.Name(), tt.limit, tt.offset)
+	}
+}
+
+func Test_DialectByName(t *testing.T) {
+	for _, name := range []string{"postgres", "mysql", "sqlite", "sqlserver", "oracle"} {
+		dialect, ok := DialectByName(name)
+		require.True(t, ok)
+		require.Equal(t, name, dialect.Name())
+	}
+
+	_, ok := DialectByName("clickhouse")
+	require.False(t, ok)
+}
+
+// testDialector is a gorm dialector unknown to gopager.
+type testDialector struct {
+	gorm.Dialector
+}
+
+func (testDialector) Name() string {
+	return "custom"
+}
+
+func Test_gormDialect_QuoteIdentifier(t *testing.T) {
+	dialect := gormDialectOf(&gorm.DB{Config: &gorm.Config{Dialector: testDialector{sqlite.Open(":memory:")}}})
+	require.IsType(t, gormDialect{}, dialect)
+
+	require.Equal(t, "`id`", dialect.QuoteIdentifier("id"))
+	// Parts are already split: dots and quotes are a part of the name.
+	require.Equal(t, "`a.b`", dialect.QuoteIdentifier("a.b"))
+	require.Equal(t, "`a``b`", dialect.QuoteIdentifier("a`b"))
+	require.Equal(t, "`t`.`a.b`", quoteColumn(dialect, `t."a.b"`))
+}
+
+func Test_DefaultCursor_ToDialectSQL(t *testing.T) {
+	cursor := NewDefaultCursor(
+		CursorElement{Column: "id", Value: 10, Operator: OperatorGT},
+		CursorElement{Column: "name", Value: "abc", Operator: OperatorLT},
+	)
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		// NULL values are the largest in PostgreSQL and Oracle: they follow "id".
+		{DialectPostgres, `(("id" > $3) OR ("id" IS NULL) OR ("id" = $4 AND "name" < $5))`},
+		{DialectMySQL, "((`id` > ?) OR (`id` = ? AND `name` < ?))"},
+		{DialectSQLite, `(("id" > ?) OR ("id" = ? AND "name" < ?))`},
+		{DialectSQLServer, "(([id] > @p3) OR ([id] = @p4 AND [name] < @p5))"},
+		{DialectOracle, `(("id" > :3) OR ("id" IS NULL) OR ("id" = :4 AND "name" < :5))`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			where, args, err := cursor.ToDialectSQL(tt.dialect, 3)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, where)
+			require.Equal(t, []driver.Value{10, 10, "abc"}, args)
+		})
+	}
+
+	t.Run("empty cursor", func(t *testing.T) {
+		where, args, err := (*DefaultCursor)(nil).ToDialectSQL(DialectOracle, 1)
+		require.NoError(t, err)
+		require.Equal(t, "1 = 1", where)
+		require.Empty(t, args)
+	})
+
+	t.Run("malformed cursor", func(t *testing.T) {
+		for _, element := range []CursorElement{
+			{Column: "1=1) OR (1", Value: 1, Operator: OperatorGT},
+			{Column: "id", Value: 1, Operator: "> 0 OR 1=1 OR 1 <"},
+		} {
+			_, _, err := NewDefaultCursor(element).ToDialectSQL(DialectPostgres, 1)
+			require.Error(t, err)
+
+			where, args := NewDefaultCursor(element).ToSQL()
+			require.Equal(t, _falseCondition, where)
+			require.Empty(t, args)
+		}
+	})
+}
+
+func Test_CursorPager_ToSQL(t *testing.T) {
+	sameDirection := Orderings{
+		{Column: "id", Direction: DirectionASC},
+		{Column: "created_at", Direction: DirectionASC},
+	}
+	forward := NewDefaultCursor(
+		CursorElement{Column: "id", Value: 10, Operator: OperatorGT},
+		CursorElement{Column: "created_at", Value: "2023-01-01", Operator: OperatorGT},
+	)
+
+	tests := []struct {
+		name     string
+		pager    *CursorPager[*DefaultCursor]
+		dialect  Dialect
+		expected SQLFragment
+	}{
+		{
+			name:    "postgres first page",
+			pager:   NewCursorPager[*DefaultCursor]().WithLimit(10).WithLookahead().WithSort(sameDirection...),
+			dialect: DialectPostgres,
+			expected: SQLFragment{
+				Where:       "TRUE",
+				OrderBy:     `"id" ASC, "created_at" ASC`,
+				LimitOffset: "LIMIT 11",
+			},
+		},
+		{
+			name:    "postgres row values",
+			pager:   NewCursorPager[*DefaultCursor]().WithLimit(10).WithCursor(forward).WithSort(sameDirection...).WithNotNullColumns("id").WithRowValueComparison(),
+			dialect: DialectPostgres,
+			expected: SQLFragment{
+				Where:       `("id", "created_at") > ($2, $3)`,
+				OrderBy:     `"id" ASC, "created_at" ASC`,
+				LimitOffset: "LIMIT 10",
+				Args:        []driver.Value{10, "2023-01-01"},
+			},
+		},
+		{
+			name:    "sqlserver row values fallback",
+			pager:   NewCursorPager[*DefaultCursor]().WithLimit(10).WithCursor(forward).WithSort(sameDirection...).WithRowValueComparison(),
+			dialect: DialectSQLServer,
+			expected: SQLFragment{
+				Where:       "(([id] > @p2) OR ([id] = @p3 AND [created_at] > @p4))",
+				OrderBy:     "[id] ASC, [created_at] ASC",
+				LimitOffset: "OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY",
+				Args:        []driver.Value{10, 10, "2023-01-01"},
+			},
+		},
+		{
+			name:    "oracle backward",
+			pager:   NewCursorPager[*DefaultCursor]().WithLimit(10).WithCursor(forward.Reversed()).WithSort(sameDirection...),
+			dialect: DialectOracle,
+			expected: SQLFragment{
+				Where:       `(("id" < :2) OR ("id" = :3 AND "created_at" < :4))`,
+				OrderBy:     `"id" DESC, "created_at" DESC`,
+				LimitOffset: "OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY",
+				Args:        []driver.Value{10, 10, "2023-01-01"},
+			},
+		},
+		{
+			name:    "sqlserver zero limit",
+			pager:   NewCursorPager[*DefaultCursor]().WithZeroLimit().WithCursor(forward).WithSort(sameDirection...),
+			dialect: DialectSQLServer,
+			expected: SQLFragment{
+				Where:       "1 = 0",
+				OrderBy:     "[id] ASC, [created_at] ASC",
+				LimitOffset: "OFFSET 9223372036854775807 ROWS",
+			},
+		},
+		{
+			name:    "mysql unlimited",
+			pager:   NewCursorPager[*DefaultCursor]().WithUnlimited().WithCursor(forward).WithSort(sameDirection...),
+			dialect: DialectMySQL,
+			expected: SQLFragment{
+				Where:   "((`id` > ?) OR (`id` = ? AND `created_at` > ?))",
+				OrderBy: "`id` ASC, `created_at` ASC",
+				Args:    []driver.Value{10, 10, "2023-01-01"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.pager.ToSQL(tt.dialect, 2)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, *got)
+		})
+	}
+
+	t.Run("pseudo cursor", func(t *testing.T) {
+		pager := NewCursorPager[*PseudoCursor]().
+			WithLimit(10).
+			WithCursor(NewPseudoCursor(20)).
+			WithSort(OrderBy{Column: "id", Direction: DirectionASC})
+
+		got, err := pager.ToSQL(DialectSQLServer, 1)
+		require.NoError(t, err)
+		require.Equal(t, SQLFragment{
+			Where:       "1 = 1",
+			OrderBy:     "[id] ASC",
+			LimitOffset: "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+		}, *got)
+	})
+
+	t.Run("invalid pager", func(t *testing.T) {
+		_, err := NewCursorPager[*DefaultCursor]().ToSQL(DialectPostgres, 1)
+		require.Error(t, err)
+	})
+}
+
+func Test_PseudoCursor_ToDialectSQL(t *testing.T) {
+	require.Equal(t, "LIMIT 10 OFFSET 20", NewPseudoCursor(20).ToDialectSQL(DialectPostgres, 10))
+	require.Equal(t, "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", NewPseudoCursor(20).ToDialectSQL(DialectOracle, 10))
+	require.Equal(t, "LIMIT 10", (*PseudoCursor)(nil).ToDialectSQL(DialectSQLite, 10))
+}
+
+func Test_CursorPager_ToSQL_Expr(t *testing.T) {
+	type user struct {
+		ID    int
+		Email string
+	}
+
+	sort := Orderings{
+		{Column: "email", Direction: DirectionASC, Expr: "LOWER(email)"},
+		{Column: "id", Direction: DirectionASC},
+	}
+	getters := Getters[user]{
+		"email": func(u user) any { return strings.ToLower(u.Email) },
+		"id":    func(u user) any { return u.ID },
+	}
+
+	pager := NewCursorPager[*DefaultCursor]().WithLimit(2).WithSort(sort...)
+	_, cursor, err := NextPageCursor(pager, []user{{1, "A@example.com"}, {2, "B@example.com"}}, getters)
+	require.NoError(t, err)
+	require.Equal(t, []CursorElement{
+		{Column: "email", Value: "b@example.com", Operator: OperatorGT},
+		{Column: "id", Value: 2, Operator: OperatorGT},
+	}, cursor.GetElements())
+
+	got, err := pager.WithCursor(cursor).ToSQL(DialectPostgres, 1)
+	require.NoError(t, err)
+	require.Equal(t, SQLFragment{
+		Where:       `(((LOWER(email)) > $1) OR ((LOWER(email)) IS NULL) OR ((LOWER(email)) = $2 AND "id" > $3))`,
+		OrderBy:     `(LOWER(email)) ASC, "id" ASC`,
+		LimitOffset: "LIMIT 2",
+		Args:        []driver.Value{"b@example.com", "b@example.com", 2},
+	}, *got)
+
+	got, err = pager.WithRowValueComparison().WithNotNullColumns("email").ToSQL(DialectPostgres, 1)
+	require.NoError(t, err)
+	require.Equal(t, `((LOWER(email)), "id") > ($1, $2)`, got.Where)
+}
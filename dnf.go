@@ -9,11 +9,19 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// _falseCondition is an always false condition valid in every dialect. Used
+// if no rows follow the cursor.
+const _falseCondition = "1 = 0"
+
 type (
 	tConjunct struct {
 		Column   string
 		Value    any
 		Operator Operator
+		// Expr is the trusted expression compared instead of Column.
+		Expr OrderByExpr
+		// Collation is applied to the compared column.
+		Collation string
 	}
 
 	tDisjunct []tConjunct
@@ -35,6 +43,7 @@ type (
 
 // toGORMExpression converts a conjunct of the form Operator(Column, Value)
 // into an SQL condition "Column Operator Value" represented as a clause.Expression.
+// The column is quoted for the dialect.
 //
 // IMPORTANT: The method uses the SQL placeholder "?".
 //
@@ -45,12 +54,13 @@ type (
 // Result:
 //
 //	"id > 123"
-func (c tConjunct) toGORMExpression() clause.Expression {
-	sqlClause, arg := c.toSQLClause()
+func (c tConjunct) toGORMExpression(dialect Dialect) clause.Expression {
+	args := newGORMArgs(dialect)
+	sqlClause := c.renderSQL(args)
 
 	return clause.Expr{
 		SQL:  sqlClause,
-		Vars: []any{arg},
+		Vars: args.vars(),
 	}
 }
 
@@ -66,9 +76,36 @@ func (c tConjunct) toGORMExpression() clause.Expression {
 //
 //	("id > ?", 123)
 func (c tConjunct) toSQLClause() (string, driver.Value) {
-	return fmt.Sprintf("%s %s ?", c.Column, c.Operator), parseAnyValue(c.Value)
+	args := newSQLArgs(_questionMarkDialect, 1)
+	sqlClause := c.renderSQL(args)
+	if len(args.values) == 0 {
+		return sqlClause, nil
+	}
+
+	return sqlClause, args.values[0]
+}
+
+// renderSQL renders the conjunct as "Column Operator <placeholder>" adding
+// the value to args, or as "Column IS [NOT] NULL". The column is quoted for
+// the dialect of args, the expression is rendered as is. The collation is
+// applied to comparisons only.
+func (c tConjunct) renderSQL(args *sqlArgs) string {
+	key := OrderBy{Column: c.Column, Expr: c.Expr, Collation: c.Collation}
+	if c.Operator.isNullCheck() {
+		return fmt.Sprintf("%s %s", key.sqlKey(args.dialect), c.Operator)
+	}
+
+	return fmt.Sprintf("%s %s %s", key.comparisonKey(args.dialect), c.Operator, args.add(c.Value))
+}
+
+// withKey returns the conjunct comparing the sort key of the ordering.
+func (c tConjunct) withKey(o OrderBy) tConjunct {
+	c.Expr, c.Collation = o.Expr, o.Collation
+	return c
 }
 
+// parseAnyValue restores the type of a value decoded from a token issued
+// without type tags. RFC 3339 strings are converted to time.Time.
 func parseAnyValue(v any) any {
 	// Try parsing a value as time.Time. If it succeeds, return time.Time.
 	// Otherwise return the original value.
@@ -94,10 +131,10 @@ func parseAnyValue(v any) any {
 
 // toGORMExpression converts a disjunct (K1, K2, K3) into a gorm expression
 // "K1 AND K2 AND K3" where each Ki is expanded via tConjunct.toGORMExpression.
-func (d tDisjunct) toGORMExpression() clause.Expression {
+func (d tDisjunct) toGORMExpression(dialect Dialect) clause.Expression {
 	andExpressions := make([]clause.Expression, 0, len(d))
 	for _, conjunct := range d {
-		andExpressions = append(andExpressions, conjunct.toGORMExpression())
+		andExpressions = append(andExpressions, conjunct.toGORMExpression(dialect))
 	}
 
 	if len(andExpressions) == 1 {
@@ -124,29 +161,32 @@ func (d tDisjunct) toGORMExpression() clause.Expression {
 //
 //	("(id > ? AND name < ?)", [5, "abc"])
 func (d tDisjunct) toSQLClause() (string, []driver.Value) {
-	andClauses := make([]string, 0, len(d))
-	andValues := make([]driver.Value, 0, len(d))
+	args := newSQLArgs(_questionMarkDialect, 1)
+	return d.renderSQL(args), args.values
+}
 
+// renderSQL renders the disjunct as "(K1 AND K2 AND K3)" adding the values to
+// args. Returns an empty string if the disjunct is empty.
+func (d tDisjunct) renderSQL(args *sqlArgs) string {
+	andClauses := make([]string, 0, len(d))
 	for _, conjunct := range d {
-		andClause, andValue := conjunct.toSQLClause()
-		andClauses = append(andClauses, andClause)
-		andValues = append(andValues, andValue)
+		andClauses = append(andClauses, conjunct.renderSQL(args))
 	}
 
 	if len(andClauses) >= 1 {
-		return fmt.Sprintf("(%s)", strings.Join(andClauses, " AND ")), andValues
+		return fmt.Sprintf("(%s)", strings.Join(andClauses, " AND "))
 	}
 
-	return "", nil
+	return ""
 }
 
 // toGORMExpression converts a DNF (tDNF) into a clause.Expression.
 // For each disjunct it calls tDisjunct.toGORMExpression and joins disjuncts with OR.
-func (d tDNF) toGORMExpression() clause.Expression {
+func (d tDNF) toGORMExpression(dialect Dialect) clause.Expression {
 	orExpressions := make([]clause.Expression, 0, len(d))
 
 	for _, disjunct := range d {
-		andExpressions := disjunct.toGORMExpression()
+		andExpressions := disjunct.toGORMExpression(dialect)
 		if andExpressions == nil {
 			continue
 		}
@@ -178,22 +218,31 @@ func (d tDNF) toGORMExpression() clause.Expression {
 //
 //	("((id < ?) OR (id = ? AND name < ?))", [10, 10, "abc"])
 func (d tDNF) toSQLClause() (string, []driver.Value) {
-	orClauses := make([]string, 0, len(d))
-	values := make([]driver.Value, 0, len(d))
+	args := newSQLArgs(_questionMarkDialect, 1)
+	ret := d.renderSQL(args)
+	if ret == "" {
+		return _questionMarkDialect.True(), nil
+	}
+
+	return ret, args.values
+}
 
+// renderSQL renders the DNF as "(D1 OR D2)" adding the values to args.
+// Returns an empty string if the DNF is empty.
+func (d tDNF) renderSQL(args *sqlArgs) string {
+	orClauses := make([]string, 0, len(d))
 	for _, disjunct := range d {
-		orClause, orValues := disjunct.toSQLClause()
+		orClause := disjunct.renderSQL(args)
 		if orClause == "" {
 			continue
 		}
 
 		orClauses = append(orClauses, orClause)
-		values = append(values, orValues...)
 	}
 
 	if len(orClauses) >= 1 {
-		return fmt.Sprintf("(%s)", strings.Join(orClauses, " OR ")), values
+		return fmt.Sprintf("(%s)", strings.Join(orClauses, " OR "))
 	}
 
-	return "TRUE", nil
+	return ""
 }
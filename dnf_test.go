@@ -10,7 +10,7 @@ import (
 
 func Test_tConjunct_toExpression(t *testing.T) {
 	timeNow := time.Now().UTC()
-	timeNowStr, _ := timeNow.MarshalText()
+	timeNowStr := timeNow.Format(time.RFC3339Nano)
 
 	tests := []struct {
 		name     string
@@ -31,10 +31,10 @@ func Test_tConjunct_toExpression(t *testing.T) {
 			wantVars: []interface{}{timeNow},
 		},
 		{
-			name:     "timestamp string should convert to timestamp",
+			name:     "timestamp-like string is bound as is",
 			conjunct: tConjunct{Column: "created_at", Operator: OperatorGT, Value: timeNowStr},
 			wantSQL:  "created_at > ?",
-			wantVars: []interface{}{timeNow},
+			wantVars: []interface{}{timeNowStr},
 		},
 		{
 			name:     "integer less than",
@@ -46,7 +46,7 @@ func Test_tConjunct_toExpression(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			expr := tt.conjunct.toGORMExpression()
+			expr := tt.conjunct.toGORMExpression(_questionMarkDialect)
 			clauseExpr := expr.(clause.Expr)
 
 			if clauseExpr.SQL != tt.wantSQL {
@@ -89,7 +89,7 @@ func Test_tDisjunct_toExpression(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			expr := tt.disjunct.toGORMExpression()
+			expr := tt.disjunct.toGORMExpression(_questionMarkDialect)
 			if (expr == nil) != tt.wantNil {
 				t.Errorf("unexpected expression result: got %v, want nil=%v", expr, tt.wantNil)
 			}
@@ -123,7 +123,7 @@ func Test_tDNF_toExpression(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			expr := tt.dnf.toGORMExpression()
+			expr := tt.dnf.toGORMExpression(_questionMarkDialect)
 			if (expr == nil) != tt.wantNil {
 				t.Errorf("unexpected expression result: got %v, want nil=%v", expr, tt.wantNil)
 			}
@@ -133,7 +133,7 @@ func Test_tDNF_toExpression(t *testing.T) {
 
 func Test_tConjunct_toSQLClause(t *testing.T) {
 	timeNow := time.Now().UTC()
-	timeNowStr, _ := timeNow.MarshalText()
+	timeNowStr := timeNow.Format(time.RFC3339Nano)
 
 	tests := []struct {
 		name     string
@@ -154,10 +154,10 @@ func Test_tConjunct_toSQLClause(t *testing.T) {
 			wantVal:  timeNow,
 		},
 		{
-			name:     "timestamp string should convert to timestamp",
+			name:     "timestamp-like string is bound as is",
 			conjunct: tConjunct{Column: "created_at", Operator: OperatorGT, Value: timeNowStr},
 			wantSQL:  "created_at > ?",
-			wantVal:  timeNow,
+			wantVal:  timeNowStr,
 		},
 		{
 			name:     "integer less than",
@@ -196,7 +196,7 @@ func Test_tConjunct_toSQLClause(t *testing.T) {
 
 func Test_tDisjunct_toSQLClause(t *testing.T) {
 	timeNow := time.Now().UTC()
-	timeNowStr, _ := timeNow.MarshalText()
+	timeNowStr := timeNow.Format(time.RFC3339Nano)
 
 	tests := []struct {
 		name     string
@@ -223,13 +223,13 @@ func Test_tDisjunct_toSQLClause(t *testing.T) {
 			wantVals: []driver.Value{5, "abc", true},
 		},
 		{
-			name: "timestamp conversion",
+			name: "timestamp-like string is bound as is",
 			disjunct: tDisjunct{
 				{Column: "created_at", Operator: OperatorGT, Value: timeNowStr},
 				{Column: "updated_at", Operator: OperatorLT, Value: timeNow},
 			},
 			wantSQL:  "(created_at > ? AND updated_at < ?)",
-			wantVals: []driver.Value{timeNow, timeNow},
+			wantVals: []driver.Value{timeNowStr, timeNow},
 		},
 		{
 			name:     "empty disjunct",
@@ -262,7 +262,7 @@ func Test_tDisjunct_toSQLClause(t *testing.T) {
 
 func Test_tDNF_toSQLClause(t *testing.T) {
 	timeNow := time.Now().UTC()
-	timeNowStr, _ := timeNow.MarshalText()
+	timeNowStr := timeNow.Format(time.RFC3339Nano)
 
 	tests := []struct {
 		name     string
@@ -304,7 +304,7 @@ func Test_tDNF_toSQLClause(t *testing.T) {
 			wantVals: []driver.Value{5, "abc", 10},
 		},
 		{
-			name: "complex DNF with timestamp conversion",
+			name: "complex DNF with timestamp-like string",
 			dnf: tDNF{
 				{
 					{Column: "created_at", Operator: OperatorGT, Value: timeNowStr},
@@ -316,7 +316,7 @@ func Test_tDNF_toSQLClause(t *testing.T) {
 				},
 			},
 			wantSQL:  "((created_at > ? AND active < ?) OR (id > ? AND price < ?))",
-			wantVals: []driver.Value{timeNow, true, 100, 99.99},
+			wantVals: []driver.Value{timeNowStr, true, 100, 99.99},
 		},
 		{
 			name:     "empty DNF",
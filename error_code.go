@@ -0,0 +1,122 @@
+package gopager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Code classifies errors returned by the library. Values match the gRPC
+// status codes of google.golang.org/grpc/codes, so a Code can be converted
+// with codes.Code(code).
+type Code uint32
+
+const (
+	CodeOK               Code = 0
+	CodeCanceled         Code = 1
+	CodeUnknown          Code = 2
+	CodeInvalidArgument  Code = 3
+	CodeDeadlineExceeded Code = 4
+	CodeInternal         Code = 13
+)
+
+// String returns the name of the code as in gRPC.
+func (c Code) String() string {
+	switch c {
+	case CodeOK:
+		return "OK"
+	case CodeCanceled:
+		return "Canceled"
+	case CodeInvalidArgument:
+		return "InvalidArgument"
+	case CodeDeadlineExceeded:
+		return "DeadlineExceeded"
+	case CodeInternal:
+		return "Internal"
+	default:
+		return "Unknown"
+	}
+}
+
+// HTTPStatus returns the HTTP status code corresponding to the code.
+func (c Code) HTTPStatus() int {
+	switch c {
+	case CodeOK:
+		return http.StatusOK
+	case CodeCanceled:
+		// Client Closed Request, as used by gRPC gateways.
+		return 499
+	case CodeInvalidArgument:
+		return http.StatusBadRequest
+	case CodeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// _clientErrors are caused by the request: a malformed or forged token, an
+// unknown sort alias or a sort changed between pages.
+var _clientErrors = []error{
+	ErrInvalidToken,
+	ErrInvalidSort,
+	ErrUnknownSortAlias,
+	ErrSortMismatch,
+	ErrInvalidPageToken,
+	ErrPageTokenRequestMismatch,
+	ErrNegativePageSize,
+}
+
+// _programmingErrors are caused by the pager setup.
+var _programmingErrors = []error{
+	ErrMissingGetter,
+	ErrEmptySort,
+	ErrLookaheadUnlimited,
+	ErrUnsupportedTotalStrategy,
+}
+
+// ErrorCode classifies an error returned by the library:
+//   - CodeInvalidArgument for client errors, e.g. ErrInvalidToken,
+//     ErrUnknownSortAlias or ErrSortMismatch;
+//   - CodeInternal for programming errors, e.g. ErrMissingGetter,
+//     ErrEmptySort or ErrLookaheadUnlimited;
+//   - CodeCanceled and CodeDeadlineExceeded for context errors;
+//   - CodeUnknown for any other error, e.g. a database error.
+//
+// Usage:
+//
+//	http.Error(w, err.Error(), gopager.ErrorCode(err).HTTPStatus())
+func ErrorCode(err error) Code {
+	var invalidArgument *InvalidArgumentError
+	switch {
+	case err == nil:
+		return CodeOK
+	case errors.As(err, &invalidArgument), isAny(err, _clientErrors):
+		return CodeInvalidArgument
+	case isAny(err, _programmingErrors):
+		return CodeInternal
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeDeadlineExceeded
+	default:
+		return CodeUnknown
+	}
+}
+
+// HTTPStatus returns the HTTP status code of an error returned by the
+// library. See ErrorCode.
+func HTTPStatus(err error) int {
+	return ErrorCode(err).HTTPStatus()
+}
+
+// isAny returns true if err matches any of targets.
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+package gopager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ErrorCode(t *testing.T) {
+	_, errToken := DecodeCursor("not a token!")
+	_, errAlias := ParseSort([]string{"nmae asc"}, ColumnMapping{"name": "users.name"})
+	_, errFormat := ParseSort([]string{"name upwards"}, ColumnMapping{"name": "users.name"})
+	_, errMismatch := DecodeCursorPager(1, NewDefaultCursor(CursorElement{Column: "id", Value: 1, Operator: OperatorGT}).String(),
+		OrderBy{Column: "name", Direction: DirectionASC})
+	_, errPageSize := DecodePageTokenRequest(testListRequest{PageSize: -1})
+	_, errGetter := CursorForRow(
+		NewCursorPager[*DefaultCursor]().WithLimit(1).WithSort(OrderBy{Column: "id", Direction: DirectionASC}),
+		1,
+		Getters[int]{},
+	)
+	_, errEmptySort := NewCursorPager[*DefaultCursor]().WithLimit(1).WithSort().ToSQL(DialectPostgres, 1)
+	_, errLookahead := NewCursorPager[*DefaultCursor]().WithUnlimited().WithLookahead().
+		WithSort(OrderBy{Column: "id", Direction: DirectionASC}).ToSQL(DialectPostgres, 1)
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		code   Code
+		status int
+	}{
+		{"nil", nil, nil, CodeOK, http.StatusOK},
+		{"invalid token", errToken, ErrInvalidToken, CodeInvalidArgument, http.StatusBadRequest},
+		{"unknown sort alias", errAlias, ErrUnknownSortAlias, CodeInvalidArgument, http.StatusBadRequest},
+		{"invalid sort", errFormat, ErrInvalidSort, CodeInvalidArgument, http.StatusBadRequest},
+		{"sort mismatch", errMismatch, ErrSortMismatch, CodeInvalidArgument, http.StatusBadRequest},
+		{"negative page size", errPageSize, ErrNegativePageSize, CodeInvalidArgument, http.StatusBadRequest},
+		{"missing getter", errGetter, ErrMissingGetter, CodeInternal, http.StatusInternalServerError},
+		{"empty sort", errEmptySort, ErrEmptySort, CodeInternal, http.StatusInternalServerError},
+		{"lookahead unlimited", errLookahead, ErrLookaheadUnlimited, CodeInternal, http.StatusInternalServerError},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), context.Canceled, CodeCanceled, 499},
+		{"deadline", context.DeadlineExceeded, context.DeadlineExceeded, CodeDeadlineExceeded, http.StatusGatewayTimeout},
+		{"unknown", errors.New("connection refused"), nil, CodeUnknown, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.target != nil {
+				require.ErrorIs(t, tt.err, tt.target)
+			}
+			require.Equal(t, tt.code, ErrorCode(tt.err))
+			require.Equal(t, tt.status, HTTPStatus(tt.err))
+		})
+	}
+}
+
+func Test_ErrorTypes(t *testing.T) {
+	_, err := ParseSort([]string{"nmae asc"}, ColumnMapping{"name": "users.name", "id": "users.id"})
+	var aliasErr *UnknownSortAliasError
+	require.ErrorAs(t, err, &aliasErr)
+	require.Equal(t, UnknownSortAliasError{Alias: "nmae", Suggestion: "name"}, *aliasErr)
+
+	_, err = CursorForRow(
+		NewCursorPager[*DefaultCursor]().WithLimit(1).WithSort(OrderBy{Column: "id", Direction: DirectionASC}),
+		1,
+		Getters[int]{},
+	)
+	var getterErr *MissingGetterError
+	require.ErrorAs(t, err, &getterErr)
+	require.Equal(t, "id", getterErr.Column)
+
+	require.Equal(t, "InvalidArgument", CodeInvalidArgument.String())
+	require.Equal(t, "Unknown", Code(42).String())
+}
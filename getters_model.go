@@ -0,0 +1,214 @@
+package gopager
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// _modelGetters caches getters built by GettersFromModel. Key is
+// modelGettersKey, value is Getters of the key type.
+var _modelGetters sync.Map
+
+// modelGettersKey identifies cached getters. The schema depends on the
+// naming strategy of the db the model is parsed with.
+type modelGettersKey struct {
+	typ    reflect.Type
+	schema *schema.Schema
+}
+
+// GettersFromModel builds Getters for every column of the GORM model T.
+// Getters are registered both for bare column names and for names qualified
+// with the model table, e.g. "id" and "users.id", so ColumnMapping values of
+// either form are resolved. Fields of embedded structs are included, a nil
+// embedded pointer yields a nil value.
+//
+// The model is parsed with the schema cache and naming strategy of db. The
+// result is cached per type and parsed schema.
+//
+// If orderings are provided, every ordering column is checked to have a
+// getter, otherwise *MissingGetterError is returned. Orderings by expressions
+// are skipped: add getters for their aliases to the result.
+func GettersFromModel[T any](db *gorm.DB, orderBy ...OrderBy) (Getters[T], error) {
+	typ := reflect.TypeFor[T]()
+
+	stmt := &gorm.Statement{DB: db}
+	err := stmt.Parse(new(T))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse model %s: %w", typ, err)
+	}
+
+	key := modelGettersKey{typ: typ, schema: stmt.Schema}
+	cached, ok := _modelGetters.Load(key)
+	if !ok {
+		cached, _ = _modelGetters.LoadOrStore(key, modelGetters[T](stmt))
+	}
+
+	ret := maps.Clone(cached.(Getters[T]))
+	for _, o := range orderBy {
+		if o.Expr != "" {
+			continue
+		}
+		if _, ok = ret[o.Column]; !ok {
+			return nil, fmt.Errorf(
+				"ordering column '%s' has no matching field in %s, closest: '%s': %w",
+				o.Column, typ, closestAlias(o.Column, lo.Keys(ret)), &MissingGetterError{Column: o.Column},
+			)
+		}
+	}
+
+	return ret, nil
+}
+
+// modelGetters builds a getter for every field of the parsed model mapped to
+// a column.
+func modelGetters[T any](stmt *gorm.Statement) Getters[T] {
+	tables := lo.Uniq(lo.Compact([]string{stmt.Schema.Table, stmt.Table}))
+	ret := make(Getters[T], len(stmt.Schema.FieldsByDBName)*(len(tables)+1))
+	for column, field := range stmt.Schema.FieldsByDBName {
+		getter := func(row T) any {
+			rv := reflect.ValueOf(row)
+			if rv.Kind() == reflect.Pointer && rv.IsNil() {
+				return nil
+			}
+
+			value, _ := field.ValueOf(context.Background(), rv)
+			return value
+		}
+
+		ret[column] = getter
+		for _, table := range tables {
+			ret[table+"."+column] = getter
+		}
+	}
+
+	return ret
+}
+
+// primaryKeyColumns returns the primary key columns of the model of db
+// qualified with the table name, and the model type.
+func primaryKeyColumns(db *gorm.DB) (reflect.Type, []string, error) {
+	if db == nil || db.Statement == nil || db.Statement.Model == nil {
+		return nil, nil, fmt.Errorf("cannot resolve primary key: query model is not set")
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	err := stmt.Parse(db.Statement.Model)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot resolve primary key: %w", err)
+	}
+	if len(stmt.Schema.PrimaryFieldDBNames) == 0 {
+		return nil, nil, fmt.Errorf("cannot resolve primary key: model %s has no primary key", stmt.Schema.Name)
+	}
+
+	table := stmt.Schema.Table
+	if db.Statement.Table != "" {
+		table = db.Statement.Table
+	}
+
+	return stmt.Schema.ModelType, lo.Map(stmt.Schema.PrimaryFieldDBNames, func(item string, _ int) string {
+		return table + "." + item
+	}), nil
+}
+
+// primaryKeyTieBreakers are the primary key columns resolved by Paginate,
+// keyed by the model type. They are shared by the copies of a pager, so the
+// pager itself is never modified.
+type primaryKeyTieBreakers struct {
+	mu     sync.Mutex
+	byType map[reflect.Type][]string
+}
+
+func (t *primaryKeyTieBreakers) store(typ reflect.Type, columns []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.byType == nil {
+		t.byType = make(map[reflect.Type][]string)
+	}
+	t.byType[typ] = columns
+}
+
+// load returns the primary key columns of rows of the type. Rows of other
+// types, e.g. structs a query is scanned into, get the key of the only
+// resolved model.
+func (t *primaryKeyTieBreakers) load(typ reflect.Type) ([]string, bool) {
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	columns, ok := t.byType[typ]
+	if !ok && len(t.byType) == 1 {
+		for _, columns = range t.byType {
+			ok = true
+		}
+	}
+
+	return columns, ok
+}
+
+// notNullColumns are the columns which cannot hold NULL values: primary keys
+// and NOT NULL fields of the query model and the columns declared with
+// CursorPager.WithNotNullColumns.
+type notNullColumns struct {
+	table   string
+	columns map[string]bool
+	// declared are compared with columns as they are given.
+	declared []string
+}
+
+// modelNotNullColumns returns the NOT NULL columns of the query model. The set
+// is empty if the model is not set or cannot be parsed.
+func modelNotNullColumns(db *gorm.DB) notNullColumns {
+	if db == nil || db.Statement == nil || db.Statement.Model == nil {
+		return notNullColumns{}
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if stmt.Parse(db.Statement.Model) != nil {
+		return notNullColumns{}
+	}
+
+	ret := notNullColumns{table: stmt.Schema.Table, columns: make(map[string]bool)}
+	if db.Statement.Table != "" {
+		ret.table = db.Statement.Table
+	}
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName != "" && (field.PrimaryKey || field.NotNull) {
+			ret.columns[field.DBName] = true
+		}
+	}
+
+	return ret
+}
+
+// has reports whether the column, possibly qualified with the table name,
+// cannot hold NULL values.
+func (n notNullColumns) has(column string) bool {
+	if slices.Contains(n.declared, column) {
+		return true
+	}
+	if len(n.columns) == 0 {
+		return false
+	}
+
+	parts, err := parseIdentifier(column)
+	if err != nil {
+		return false
+	}
+	if len(parts) > 1 && parts[len(parts)-2] != n.table {
+		return false
+	}
+
+	return n.columns[parts[len(parts)-1]]
+}
@@ -0,0 +1,114 @@
+package gopager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Timestamps is exported: GORM skips unexported embedded structs.
+type Timestamps struct {
+	CreatedAt time.Time
+}
+
+type testModelAudit struct {
+	Author string
+}
+
+type testModelUser struct {
+	ID int64 `gorm:"primaryKey"`
+	Timestamps
+	Audit   *testModelAudit `gorm:"embedded;embeddedPrefix:audit_"`
+	Name    string          `gorm:"column:full_name"`
+	Ignored string          `gorm:"-"`
+}
+
+func Test_GettersFromModel(t *testing.T) {
+	_, db, _, err := newGORMPostgresMock()
+	require.NoError(t, err)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	user := testModelUser{
+		ID:         1,
+		Timestamps: Timestamps{CreatedAt: createdAt},
+		Audit:      &testModelAudit{Author: "admin"},
+		Name:       "John",
+	}
+
+	getters, err := GettersFromModel[testModelUser](db)
+	require.NoError(t, err)
+	require.NotContains(t, getters, "ignored")
+
+	tests := []struct {
+		column   string
+		expected any
+	}{
+		{"id", int64(1)},
+		{"test_model_users.id", int64(1)},
+		{"created_at", createdAt},
+		{"full_name", "John"},
+		{"test_model_users.full_name", "John"},
+		{"audit_author", "admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			require.Contains(t, getters, tt.column)
+			require.Equal(t, tt.expected, getters[tt.column](user))
+		})
+	}
+
+	t.Run("nil embedded pointer", func(t *testing.T) {
+		require.Nil(t, getters["audit_author"](testModelUser{}))
+	})
+
+	t.Run("pointer model", func(t *testing.T) {
+		getters, err := GettersFromModel[*testModelUser](db)
+		require.NoError(t, err)
+		require.Equal(t, "John", getters["full_name"](&user))
+		require.Nil(t, getters["full_name"](nil))
+	})
+
+	t.Run("next page cursor", func(t *testing.T) {
+		orderBy := []OrderBy{
+			{Column: "test_model_users.created_at", Direction: DirectionDESC},
+			{Column: "id", Direction: DirectionASC},
+		}
+		getters, err := GettersFromModel[testModelUser](db, orderBy...)
+		require.NoError(t, err)
+
+		pager := NewCursorPager[*DefaultCursor]().WithLimit(1).WithSort(orderBy...)
+		_, cursor, err := NextPageCursor(pager, []testModelUser{user}, getters)
+		require.NoError(t, err)
+		require.Equal(t, []CursorElement{
+			{Column: "test_model_users.created_at", Value: createdAt, Operator: OperatorLT},
+			{Column: "id", Value: int64(1), Operator: OperatorGT},
+		}, cursor.GetElements())
+	})
+
+	t.Run("unknown ordering column", func(t *testing.T) {
+		_, err := GettersFromModel[testModelUser](db, OrderBy{Column: "fullname", Direction: DirectionASC})
+		require.ErrorContains(t, err, "closest: 'full_name'")
+		require.ErrorIs(t, err, ErrMissingGetter)
+		var missing *MissingGetterError
+		require.ErrorAs(t, err, &missing)
+		require.Equal(t, "fullname", missing.Column)
+		require.Equal(t, CodeInternal, ErrorCode(err))
+	})
+
+	t.Run("naming strategy", func(t *testing.T) {
+		prefixed, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+			NamingStrategy: schema.NamingStrategy{TablePrefix: "app_"},
+		})
+		require.NoError(t, err)
+
+		// Getters cached for db are not reused with another naming strategy.
+		getters, err := GettersFromModel[testModelUser](prefixed)
+		require.NoError(t, err)
+		require.Contains(t, getters, "app_test_model_users.id")
+		require.NotContains(t, getters, "test_model_users.id")
+	})
+}
@@ -0,0 +1,156 @@
+package gopager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// _maxIdentifierParts is the maximal number of parts of a column
+// identifier: schema.table.column.
+const _maxIdentifierParts = 3
+
+// _identifierQuotes maps opening quote characters to closing ones.
+var _identifierQuotes = map[byte]byte{
+	'"': '"',
+	'`': '`',
+	'[': ']',
+}
+
+// parseIdentifier parses a possibly qualified column identifier of the form
+// [[schema.]table.]column. Every part is either a bare identifier consisting
+// of letters, digits and underscores and not starting with a digit, or an
+// identifier in double quotes, backticks or brackets. A closing quote inside a quoted part is
+// escaped by doubling it. Returns unquoted parts.
+func parseIdentifier(s string) ([]string, error) {
+	var ret []string
+	for rest := s; ; {
+		part, tail, err := cutIdentifierPart(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid identifier '%s': %w", s, err)
+		}
+		ret = append(ret, part)
+
+		if tail == "" {
+			break
+		}
+		if tail[0] != '.' {
+			return nil, fmt.Errorf("invalid identifier '%s': unexpected symbol '%c'", s, tail[0])
+		}
+		rest = tail[1:]
+	}
+
+	if len(ret) > _maxIdentifierParts {
+		return nil, fmt.Errorf("invalid identifier '%s': too many parts", s)
+	}
+
+	return ret, nil
+}
+
+// cutIdentifierPart cuts the first part of an identifier. Returns the
+// unquoted part and the rest of the string starting with the separator.
+func cutIdentifierPart(s string) (string, string, error) {
+	if s == "" {
+		return "", "", fmt.Errorf("empty part")
+	}
+
+	closing, quoted := _identifierQuotes[s[0]]
+	if !quoted {
+		end := 0
+		for end < len(s) && isIdentifierSymbol(s[end], end == 0) {
+			end++
+		}
+		if end == 0 {
+			return "", "", fmt.Errorf("unexpected symbol '%c'", s[0])
+		}
+
+		return s[:end], s[end:], nil
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != closing {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		// Doubled closing quote is an escaped quote character.
+		if i+1 < len(s) && s[i+1] == closing {
+			b.WriteByte(closing)
+			i++
+			continue
+		}
+
+		if b.Len() == 0 {
+			return "", "", fmt.Errorf("empty quoted part")
+		}
+
+		return b.String(), s[i+1:], nil
+	}
+
+	return "", "", fmt.Errorf("unbalanced quote '%c'", s[0])
+}
+
+// isIdentifierSymbol returns true if c is allowed in a bare identifier.
+func isIdentifierSymbol(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	default:
+		return false
+	}
+}
+
+// quoteColumn renders a column identifier with every part quoted for the
+// dialect. Identifiers which cannot be parsed are quoted as a whole, so they
+// never break out of the identifier context. The plain dialect renders
+// columns as they are given: orderings and cursors are checked with
+// parseIdentifier before they are rendered with it.
+func quoteColumn(dialect Dialect, column string) string {
+	if _, ok := dialect.(plainDialect); ok {
+		return column
+	}
+
+	parts, err := parseIdentifier(column)
+	if err != nil {
+		return dialect.QuoteIdentifier(column)
+	}
+
+	for i := range parts {
+		parts[i] = dialect.QuoteIdentifier(parts[i])
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// isCollationName returns true if s is a well-formed collation name, e.g.
+// "C", "NOCASE", "utf8mb4_0900_ai_ci" or "en-US-x-icu".
+func isCollationName(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isIdentifierSymbol(s[i], false) && s[i] != '-' && s[i] != '.' && s[i] != '@' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isBareIdentifier returns true if s is an identifier which needs no quotes.
+func isBareIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isIdentifierSymbol(s[i], i == 0) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,85 @@
+package gopager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseIdentifier(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected []string
+		ok       bool
+	}{
+		{"id", []string{"id"}, true},
+		{"users.id", []string{"users", "id"}, true},
+		{"public.users.created_at", []string{"public", "users", "created_at"}, true},
+		{`"order"`, []string{"order"}, true},
+		{"`user`.`name`", []string{"user", "name"}, true},
+		{"[dbo].[order]", []string{"dbo", "order"}, true},
+		{`"a.b"."c"`, []string{"a.b", "c"}, true},
+		{`"say ""hi"""`, []string{`say "hi"`}, true},
+		{"_col1", []string{"_col1"}, true},
+		{"", nil, false},
+		{"1col", nil, false},
+		{"a..b", nil, false},
+		{"a.", nil, false},
+		{".a", nil, false},
+		{`"order`, nil, false},
+		{`"order"x`, nil, false},
+		{`""`, nil, false},
+		{"`a\"", nil, false},
+		{"'id'", nil, false},
+		{"id; DROP TABLE users", nil, false},
+		{"id--", nil, false},
+		{"a.b.c.d", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseIdentifier(tt.in)
+			if !tt.ok {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func Test_quoteColumn(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		in       string
+		expected string
+	}{
+		{DialectPostgres, "public.user.order", `"public"."user"."order"`},
+		{DialectPostgres, "`a\"b`", `"a""b"`},
+		{DialectMySQL, `"order"`, "`order`"},
+		{DialectMySQL, "`a``b`", "`a``b`"},
+		{DialectSQLite, "[order]", `"order"`},
+		{DialectSQLServer, `t."a]b"`, "[t].[a]]b]"},
+		{DialectOracle, "users.id", `"users"."id"`},
+		{_questionMarkDialect, `t."order"`, `t."order"`},
+		// Unparsable identifiers never escape quoting.
+		{DialectPostgres, `a"; DROP TABLE x; --`, `"a""; DROP TABLE x; --"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name()+" "+tt.in, func(t *testing.T) {
+			require.Equal(t, tt.expected, quoteColumn(tt.dialect, tt.in))
+		})
+	}
+}
+
+func Test_Orderings_ToDialectSQL(t *testing.T) {
+	orderings := Orderings{
+		{Column: "order", Direction: DirectionASC},
+		{Column: "u.id", Direction: DirectionDESC},
+	}
+
+	require.Equal(t, `"order" ASC, "u"."id" DESC`, orderings.ToDialectSQL(DialectPostgres))
+	require.Equal(t, "`order` ASC, `u`.`id` DESC", orderings.ToDialectSQL(DialectMySQL))
+	require.Equal(t, "order ASC, u.id DESC", orderings.ToSQL())
+}
@@ -1,15 +1,58 @@
 package gopager
 
 import (
-	"bytes"
 	"database/sql/driver"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/samber/lo"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrMissingGetter is returned when Getters have no getter for an ordering
+// column. Use errors.As with *MissingGetterError to get the column.
+var ErrMissingGetter = errors.New("missing getter")
+
+// MissingGetterError describes an ordering column without a getter.
+type MissingGetterError struct {
+	// Column is the ordering column.
+	Column string
+}
+
+func (e *MissingGetterError) Error() string {
+	return fmt.Sprintf("cannot find getter for column '%s' met in ordering", e.Column)
+}
+
+func (e *MissingGetterError) Unwrap() error {
+	return ErrMissingGetter
+}
+
+// ErrSortMismatch is returned when the requested orderings differ from the
+// orderings a cursor was built for. Use errors.As with *SortMismatchError to
+// get both orderings.
+var ErrSortMismatch = errors.New("cursor sort mismatch")
+
+// SortMismatchError describes orderings conflicting with a cursor.
+type SortMismatchError struct {
+	// Expected are the orderings the cursor was built for.
+	Expected Orderings
+	// Actual are the requested orderings.
+	Actual Orderings
+}
+
+func (e *SortMismatchError) Error() string {
+	return fmt.Sprintf("%s: expected '%s', got '%s'", ErrSortMismatch, e.Expected.ToSQL(), e.Actual.ToSQL())
+}
+
+func (e *SortMismatchError) Unwrap() error {
+	return ErrSortMismatch
+}
+
 // DefaultCursor represents a pagination token that defines the starting
 // position for the requested page. An empty token means the beginning of the dataset.
 //
@@ -19,8 +62,26 @@ import (
 // The token consists of a set of conditions of the form:
 //
 //	[(C1, O1, V1), (C2, O2, V2)... (Cn, On, Vn)]
+//
+// A backward cursor points to the previous page: its operators are inverted
+// and the dataset is fetched with inverted orderings.
+//
+// A cursor may also carry a stop before bound, which limits the dataset to
+// the rows preceding the position of another cursor, see WithStopBefore.
 type DefaultCursor struct {
 	elements []CursorElement
+	// stopBefore are the elements of the bound. Operators follow the
+	// requested orderings even for a backward cursor.
+	stopBefore []CursorElement
+	// sort are the requested orderings the cursor was built for, with the
+	// placement of NULL values, expressions and collations. Nil for cursors
+	// built by hand and for tokens issued without them.
+	sort        Orderings
+	backward    bool
+	codec       *TokenCodec
+	issuedAt    time.Time
+	expiresAt   time.Time
+	fingerprint []byte
 }
 
 func NewCursor(elements ...CursorElement) *DefaultCursor {
@@ -34,48 +95,100 @@ func NewDefaultCursor(elements ...CursorElement) *DefaultCursor {
 }
 
 // DecodeCursor attempts to parse a base64-encoded string into *DefaultCursor.
+// Use TokenCodec.DecodeCursor to decode signed tokens.
 func DecodeCursor(b64String string) (*DefaultCursor, error) {
-	if len(b64String) == 0 {
-		return nil, nil
+	return (*TokenCodec)(nil).DecodeCursor(b64String)
+}
+
+// String - implements fmt.Stringer. Intended for logging only, use Encode
+// to issue tokens. If the cursor cannot be encoded, returns a description of
+// the error, which is never a valid token: an empty string always means an
+// empty cursor.
+func (c *DefaultCursor) String() string {
+	token, err := c.Encode()
+	if err != nil {
+		return fmt.Sprintf("%%!s(*gopager.DefaultCursor: %v)", err)
+	}
+
+	return token
+}
+
+// Encode converts the cursor into a token using the cursor TokenCodec. An
+// empty cursor is encoded into an empty token, unless it keeps the orderings
+// it was built for, e.g. the first partition of SplitPartitions.
+func (c *DefaultCursor) Encode() (string, error) {
+	if c.IsEmpty() && (c == nil || len(c.sort) == 0) {
+		return "", nil
 	}
 
-	jsonData, err := _encoder.DecodeString(b64String)
+	cursorCodec := c.codec.GetCursorCodec()
+	body, err := cursorCodec.MarshalElements(c.elements)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode base64 encoded cursor: %w", err)
+		return "", err
 	}
 
-	var elems []CursorElement
-	if err = json.Unmarshal(jsonData, &elems); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal json encoded cursor: %w", err)
+	var stopBefore []byte
+	if len(c.stopBefore) != 0 {
+		stopBefore, err = cursorCodec.MarshalElements(c.stopBefore)
+		if err != nil {
+			return "", err
+		}
 	}
 
-	return &DefaultCursor{
-		elements: elems,
-	}, nil
+	return c.codec.encode(tokenEnvelope{
+		format:      cursorCodec.Format(),
+		backward:    c.backward,
+		issuedAt:    c.issuedAt,
+		expiresAt:   c.expiresAt,
+		fingerprint: c.fingerprint,
+		stopBefore:  stopBefore,
+		sort:        marshalOrderings(c.sort),
+		body:        body,
+	})
 }
 
-// String - implements fmt.Stringer.
-func (c *DefaultCursor) String() string {
-	if c == nil || len(c.elements) == 0 {
-		return ""
+// IsEmpty - implements Cursor. A cursor with a stop before bound only is
+// not empty: it starts at the beginning of the dataset, but filters it.
+func (c *DefaultCursor) IsEmpty() bool {
+	return c == nil || (len(c.elements) == 0 && len(c.stopBefore) == 0)
+}
+
+// GetStopBefore returns the elements of the stop before bound. Nil means the
+// cursor is not bounded.
+func (c *DefaultCursor) GetStopBefore() []CursorElement {
+	if c == nil {
+		return nil
 	}
 
-	jTok, err := json.Marshal(c.elements)
-	if err != nil {
-		panic(fmt.Errorf("cannot marshal cursor value: %w", err))
+	return c.stopBefore
+}
+
+// WithStopBefore bounds the cursor: the rows fetched by the other cursor are
+// excluded, i.e. the dataset stops before the position the other cursor
+// starts from. The bound is kept in cursors of the next pages and in tokens.
+// Nil or an empty cursor removes the bound.
+//
+// Use it to split a dataset into ranges scanned independently: a range starts
+// from a cursor and stops before the cursor of the next range.
+//
+// IMPORTANT:
+// The other cursor must be a forward cursor built for the same orderings.
+func (c *DefaultCursor) WithStopBefore(other *DefaultCursor) *DefaultCursor {
+	if c == nil {
+		c = new(DefaultCursor)
 	}
 
-	var buf bytes.Buffer
-	if err = json.Compact(&buf, jTok); err != nil {
-		panic(fmt.Errorf("cannot compact cursor value: %w", err))
+	c.stopBefore = other.GetElements()
+	if len(c.sort) == 0 && other != nil {
+		c.sort = other.sort
 	}
 
-	return _encoder.EncodeToString(buf.Bytes())
+	return c
 }
 
-// IsEmpty - implements Cursor.
-func (c *DefaultCursor) IsEmpty() bool {
-	return c == nil || len(c.elements) == 0
+// isStart reports whether the cursor points to the beginning of the dataset.
+func (c *DefaultCursor) isStart() bool {
+	return len(c.GetElements()) == 0
 }
 
 // GetElements returns token elements. Cursor elements are a compressed set
@@ -104,30 +217,287 @@ func (c *DefaultCursor) WithElements(elements []CursorElement) *DefaultCursor {
 	return c
 }
 
+// GetOrderings returns the orderings the cursor was built for. Cursors built
+// by NextPageCursor, PrevPageCursor and CursorForRow keep the orderings of
+// the pager, including the placement of NULL values, expressions and
+// collations. For other cursors, e.g. built by hand, the orderings are
+// restored from the columns and operators or, if the cursor has only a stop
+// before bound, from the bound.
+func (c *DefaultCursor) GetOrderings() Orderings {
+	if c != nil && len(c.sort) != 0 {
+		return slices.Clone(c.sort)
+	}
+
+	return c.elementOrderings()
+}
+
+// elementOrderings returns the orderings restored from the columns and
+// operators of the elements or, if there are none, of the bound.
+func (c *DefaultCursor) elementOrderings() Orderings {
+	elements, backward := c.GetElements(), c.IsBackward()
+	if len(elements) == 0 {
+		elements, backward = c.GetStopBefore(), false
+	}
+
+	ret := make(Orderings, 0, len(elements))
+	for _, elem := range elements {
+		var direction Direction
+		// Invalid operators are kept as an invalid direction and rejected on validation.
+		if elem.Operator.Valid() {
+			direction = elem.Operator.ForOrdering()
+			if backward {
+				direction = direction.Invert()
+			}
+		}
+
+		ret = append(ret, OrderBy{Column: elem.Column, Direction: direction})
+	}
+
+	return ret
+}
+
+// checkSortPrefix returns *SortMismatchError if the orderings are not a
+// prefix of the cursor orderings. Orderings may be completed later, e.g. with
+// a tie-breaker, so the full match is checked on validation.
+func (c *DefaultCursor) checkSortPrefix(orderings Orderings) error {
+	expected := c.GetOrderings()
+	if len(expected) == 0 || len(orderings) == 0 {
+		return nil
+	}
+
+	if len(orderings) > len(expected) || !c.matchesSort(expected[:len(orderings)], orderings) {
+		return &SortMismatchError{Expected: expected, Actual: orderings}
+	}
+
+	return nil
+}
+
+// matchesSort returns true if the orderings match the expected cursor
+// orderings. Placement of NULL values, expressions and collations are
+// compared only if the cursor keeps them.
+func (c *DefaultCursor) matchesSort(expected Orderings, orderings Orderings) bool {
+	if len(expected) != len(orderings) {
+		return false
+	}
+
+	for i, ordering := range orderings {
+		if len(c.sort) == 0 {
+			ordering = OrderBy{Column: ordering.Column, Direction: ordering.Direction}
+		}
+		if ordering != expected[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsBackward returns true if the cursor points to the previous page.
+func (c *DefaultCursor) IsBackward() bool {
+	return c != nil && c.backward
+}
+
+// WithBackward marks the cursor as pointing to the previous page. Operators of
+// the elements must be inverted relative to the orderings.
+func (c *DefaultCursor) WithBackward(backward bool) *DefaultCursor {
+	if c == nil {
+		c = new(DefaultCursor)
+	}
+
+	c.backward = backward
+
+	return c
+}
+
+// Reversed returns a copy of the cursor pointing in the opposite direction
+// from the same position: a cursor for the rows after a row becomes a cursor
+// for the rows before it and vice versa.
+func (c *DefaultCursor) Reversed() *DefaultCursor {
+	if c == nil {
+		return nil
+	}
+
+	ret := *c
+	ret.backward = !c.backward
+	ret.elements = reversedElements(c.elements)
+
+	return &ret
+}
+
+// GetTokenCodec returns the codec used to encode the cursor.
+func (c *DefaultCursor) GetTokenCodec() *TokenCodec {
+	if c == nil {
+		return nil
+	}
+
+	return c.codec
+}
+
+// WithTokenCodec sets the codec used to encode the cursor.
+func (c *DefaultCursor) WithTokenCodec(codec *TokenCodec) *DefaultCursor {
+	if c == nil {
+		c = new(DefaultCursor)
+	}
+
+	c.codec = codec
+
+	return c
+}
+
+// GetIssuedAt returns the time the cursor was issued at. Zero time means
+// the issue time is unknown.
+func (c *DefaultCursor) GetIssuedAt() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+
+	return c.issuedAt
+}
+
+// GetExpiresAt returns the time after which the cursor token is rejected.
+// Zero time means the token never expires.
+func (c *DefaultCursor) GetExpiresAt() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+
+	return c.expiresAt
+}
+
 // Apply - implements Cursor. Applies filter-based offset to the gorm query.
 func (c *DefaultCursor) Apply(db *gorm.DB) *gorm.DB {
-	exp := c.toDNF().toGORMExpression()
-	if exp == nil {
+	err := c.check()
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("cannot apply cursor: %w", err))
 		return db
 	}
 
-	return db.Clauses(exp)
+	return c.apply(db, cursorOptions{})
+}
+
+// check returns an error if any element of the cursor or of its bound cannot
+// be rendered. Decoded cursors are always checked, see TokenCodec.DecodeCursor.
+func (c *DefaultCursor) check() error {
+	for _, elements := range [][]CursorElement{c.GetElements(), c.GetStopBefore()} {
+		for i := range elements {
+			err := elements[i].check()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// apply - implements Cursor. With row values enabled the filter is rendered
+// as a row value comparison if every element has the same operator and the
+// dialect supports it, otherwise as a DNF.
+func (c *DefaultCursor) apply(db *gorm.DB, opts cursorOptions) *gorm.DB {
+	dialect := gormDialectOf(db)
+	for _, cond := range c.conditions(opts) {
+		db = db.Clauses(cond.toGORMExpression(dialect, opts.rowValues))
+	}
+
+	return db
 }
 
 // ToSQL - implements Cursor. Returns the SQL expression representing the filter.
+// Columns are rendered as they are given. A cursor with a malformed column or
+// operator, which can only be built by hand, is rendered as the always false
+// condition "1 = 0": use ToDialectSQL to get the error.
 //
 // Usage:
 //
 //	query := fmt.Sprintf("SELECT * FROM table WHERE %s", p.ToSQL())
+//
+// Deprecated: columns are rendered as they are given, so reserved words such
+// as "order" or "t.select" break the query. Use ToDialectSQL or
+// CursorPager.ToSQL.
 func (c *DefaultCursor) ToSQL() (string, []driver.Value) {
-	if c.IsEmpty() {
-		return "TRUE", nil
+	if c.check() != nil {
+		return _falseCondition, nil
+	}
+
+	args := newSQLArgs(_questionMarkDialect, 1)
+	where, _ := c.renderSQL(args, cursorOptions{})
+
+	return where, args.values
+}
+
+// conditions returns the keyset conditions of the cursor: the rows following
+// the elements and the rows up to the stop before bound. The options are
+// those of the fetch, i.e. the orderings are inverted for a backward cursor.
+func (c *DefaultCursor) conditions(opts cursorOptions) []keysetCondition {
+	ret := make([]keysetCondition, 0, 2)
+	if len(c.GetElements()) != 0 {
+		ret = append(ret, keysetCondition{elements: c.elements, sort: opts.sort, notNull: opts.notNull})
+	}
+
+	if len(c.GetStopBefore()) != 0 {
+		// The bound follows the requested orderings.
+		sort := opts.sort
+		if c.backward {
+			sort = sort.Invert()
+		}
+		ret = append(ret, keysetCondition{elements: c.stopBefore, sort: sort, notNull: opts.notNull, upTo: true})
+	}
+
+	return ret
+}
+
+// ToDialectSQL returns the SQL condition representing the filter rendered for
+// the dialect. Columns are quoted for the dialect. Placeholders are numbered
+// starting from argIndex, so the condition can be merged into a query with
+// other arguments. Returns an always true condition if the cursor is empty
+// and an error if a column or an operator of the cursor is malformed.
+//
+// Usage:
+//
+//	where, args, err := c.ToDialectSQL(gopager.DialectPostgres, len(queryArgs)+1)
+//	query := fmt.Sprintf("SELECT * FROM table WHERE name = $1 AND %s", where)
+func (c *DefaultCursor) ToDialectSQL(dialect Dialect, argIndex int) (string, []driver.Value, error) {
+	err := c.check()
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot render cursor: %w", err)
+	}
+
+	args := newSQLArgs(dialect, argIndex)
+	where, _ := c.renderSQL(args, cursorOptions{})
+
+	return where, args.values, nil
+}
+
+// renderSQL - implements Cursor. The conditions are joined with AND.
+func (c *DefaultCursor) renderSQL(args *sqlArgs, opts cursorOptions) (string, int) {
+	conditions := c.conditions(opts)
+	if len(conditions) == 0 {
+		return args.dialect.True(), 0
 	}
 
-	return c.toDNF().toSQLClause()
+	ret := make([]string, 0, len(conditions))
+	for _, cond := range conditions {
+		ret = append(ret, cond.renderSQL(args, opts.rowValues))
+	}
+
+	return strings.Join(ret, " AND "), 0
 }
 
-// toDNF converts DefaultCursor to tDNF.
+// keysetCondition selects the rows following the elements in the orderings,
+// or with upTo the rows preceding them and the row at them, i.e. the rows
+// which do not follow them.
+type keysetCondition struct {
+	elements []CursorElement
+	// sort are the orderings the elements were built for. Expressions,
+	// collations and placement of NULL values are taken from them if they
+	// match the elements.
+	sort Orderings
+	// notNull are the columns known to hold no NULL values.
+	notNull notNullColumns
+	upTo    bool
+}
+
+// toDNF converts the condition to tDNF.
 //
 // IMPORTANT:
 // The token MUST always include a condition on a unique column!
@@ -142,59 +512,266 @@ func (c *DefaultCursor) ToSQL() (string, []driver.Value) {
 //
 // In this form the token represents a DNF sufficient for filtering. This allows
 // us to unambiguously determine the position from which to continue fetching data.
-func (c *DefaultCursor) toDNF() tDNF {
-	if c.IsEmpty() {
-		return nil
-	}
+//
+// An upTo condition inverts the operators and includes the equality:
+//
+//	(C1 !O1 V1) or (C1 = V1 and C2 !O2= V2)
+//
+// NULL values are compared with IS NULL / IS NOT NULL according to their
+// placement in the orderings and the dialect. Returns an empty DNF if no rows
+// follow the elements.
+func (k keysetCondition) toDNF(dialect Dialect) tDNF {
+	elements, orderings := k.compared()
+
+	var (
+		dnf        = make(tDNF, 0, len(elements)+1)
+		equalities = make(tDisjunct, 0, len(elements))
+		inclusive  bool
+	)
+	for i, elem := range elements {
+		conjuncts := elem.toConjunctsAfter(dialect, orderings[i].Nulls, k.isNotNull(elements, i))
+
+		// The row at the elements is merged into the last comparison when
+		// no NULL values are involved.
+		if k.upTo && i == len(elements)-1 && len(conjuncts) == 1 && conjuncts[0].Operator == elem.Operator {
+			conjuncts[0].Operator = elem.Operator.orEqual()
+			inclusive = true
+		}
 
-	dnf := make(tDNF, 0, len(c.elements))
-	for i := range c.elements {
-		previousElementsWithEqualityCondition := lo.Map(c.elements[:i], func(item CursorElement, _ int) tConjunct {
-			return item.toConjunctWithEqualityCondition()
-		})
+		for _, conjunct := range conjuncts {
+			disjunct := make(tDisjunct, 0, len(equalities)+1)
+			disjunct = append(disjunct, equalities...)
+			disjunct = append(disjunct, conjunct.withKey(orderings[i]))
 
-		disjunct := make([]tConjunct, 0, len(previousElementsWithEqualityCondition)+1)
-		disjunct = append(disjunct, previousElementsWithEqualityCondition...)
-		disjunct = append(disjunct, tConjunct(c.elements[i]))
+			dnf = append(dnf, disjunct)
+		}
+
+		equalities = append(equalities, elem.toConjunctWithEqualityCondition().withKey(orderings[i]))
+	}
 
-		dnf = append(dnf, disjunct)
+	if k.upTo && !inclusive {
+		dnf = append(dnf, equalities)
 	}
 
 	return dnf
 }
 
+// compared returns the elements and the orderings they are compared in:
+// reversed elements and inverted orderings for an upTo condition. Expressions
+// and collations are known only from the pager, so the orderings are empty
+// if sort does not match the elements.
+func (k keysetCondition) compared() ([]CursorElement, Orderings) {
+	elements, sort := k.elements, k.sort
+	if k.upTo {
+		elements, sort = reversedElements(elements), sort.Invert()
+	}
+
+	orderings := make(Orderings, len(elements))
+	if len(sort) == len(elements) {
+		orderings = sort
+	}
+
+	return elements, orderings
+}
+
+// hasNullsAfter returns true if NULL values of a column which may hold them
+// follow the values of the elements.
+func (k keysetCondition) hasNullsAfter(dialect Dialect) bool {
+	elements, orderings := k.compared()
+	for i, elem := range elements {
+		if elem.Operator.Valid() && orderings[i].Nulls.last(dialect, elem.Operator.ForOrdering()) &&
+			!k.isNotNull(elements, i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isNotNull returns true if the column of the i-th element holds no NULL
+// values: it is known to be NOT NULL or it is the last, unique column. NULL
+// values are equal in orderings, so the column they make the rows unique in
+// cannot hold them.
+func (k keysetCondition) isNotNull(elements []CursorElement, i int) bool {
+	return i == len(elements)-1 || k.notNull.has(elements[i].Column)
+}
+
+// useRowValues returns true if the condition can be rendered as a row value
+// comparison. NULL values cannot be compared this way.
+func (k keysetCondition) useRowValues(dialect Dialect, rowValues bool) bool {
+	return rowValues &&
+		dialect.SupportsRowValues() &&
+		k.hasUniformOperator() &&
+		k.sort.hasDefaultNulls() &&
+		!lo.SomeBy(k.elements, func(item CursorElement) bool { return isNullValue(item.Value) }) &&
+		!k.hasNullsAfter(dialect)
+}
+
+// hasUniformOperator returns true if the condition has several elements and
+// all of them share the same operator, i.e. the orderings have the same
+// direction.
+func (k keysetCondition) hasUniformOperator() bool {
+	if len(k.elements) < 2 {
+		return false
+	}
+
+	return lo.EveryBy(k.elements, func(item CursorElement) bool {
+		return item.Operator == k.elements[0].Operator
+	})
+}
+
+// toGORMExpression converts the condition into a gorm expression.
+func (k keysetCondition) toGORMExpression(dialect Dialect, rowValues bool) clause.Expression {
+	if k.useRowValues(dialect, rowValues) {
+		return k.toRowValueExpression(dialect)
+	}
+
+	dnf := k.toDNF(dialect)
+	if len(dnf) == 0 {
+		return clause.Expr{SQL: _falseCondition}
+	}
+
+	return dnf.toGORMExpression(dialect)
+}
+
+// renderSQL renders the condition adding its values to args.
+func (k keysetCondition) renderSQL(args *sqlArgs, rowValues bool) string {
+	if k.useRowValues(args.dialect, rowValues) {
+		return k.renderRowValueSQL(args)
+	}
+
+	dnf := k.toDNF(args.dialect)
+	if len(dnf) == 0 {
+		return _falseCondition
+	}
+
+	return dnf.renderSQL(args)
+}
+
+// toRowValueExpression converts the condition into a row value comparison.
+// All elements must have the same operator.
+//
+// Example:
+//
+//	[(a, >, 1), (b, >, 2)]
+//
+// Result:
+//
+//	"(a, b) > (?, ?)", [1, 2]
+func (k keysetCondition) toRowValueExpression(dialect Dialect) clause.Expression {
+	args := newGORMArgs(dialect)
+	sql := k.renderRowValueSQL(args)
+
+	return clause.Expr{
+		SQL:  sql,
+		Vars: args.vars(),
+	}
+}
+
+// renderRowValueSQL renders the condition as a row value comparison adding
+// the values to args. All elements must have the same operator. Expressions
+// and collations are taken from sort if it matches the elements.
+func (k keysetCondition) renderRowValueSQL(args *sqlArgs) string {
+	columns := make([]string, 0, len(k.elements))
+	placeholders := make([]string, 0, len(k.elements))
+	for i, elem := range k.elements {
+		key := OrderBy{Column: elem.Column}
+		if len(k.sort) == len(k.elements) {
+			key.Expr, key.Collation = k.sort[i].Expr, k.sort[i].Collation
+		}
+
+		columns = append(columns, key.comparisonKey(args.dialect))
+		placeholders = append(placeholders, args.add(elem.Value))
+	}
+
+	operator := k.elements[0].Operator
+	if k.upTo {
+		operator = operator.ForOrdering().Invert().ForOperator().orEqual()
+	}
+
+	return fmt.Sprintf(
+		"(%s) %s (%s)",
+		strings.Join(columns, ", "),
+		operator,
+		strings.Join(placeholders, ", "),
+	)
+}
+
+// reversedElements returns the elements with inverted operators. Invalid
+// operators are kept as is and rejected on validation.
+func reversedElements(elements []CursorElement) []CursorElement {
+	ret := make([]CursorElement, 0, len(elements))
+	for _, elem := range elements {
+		if elem.Operator.Valid() {
+			elem.Operator = elem.Operator.ForOrdering().Invert().ForOperator()
+		}
+		ret = append(ret, elem)
+	}
+
+	return ret
+}
+
 // validate - implements Cursor.
 func (c *DefaultCursor) validate(orderings Orderings) error {
-	if c.IsEmpty() {
-		return nil
+	if len(c.GetElements()) != 0 {
+		// Backward cursor fetches the dataset with inverted orderings.
+		fetched := orderings
+		if c.backward {
+			fetched = orderings.Invert()
+		}
+
+		err := c.validateElements(c.elements, fetched, orderings)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(c.GetStopBefore()) != 0 {
+		err := c.validateElements(c.stopBefore, orderings, orderings)
+		if err != nil {
+			return err
+		}
 	}
 
+	if c != nil && len(c.sort) != 0 && !c.matchesSort(c.sort, orderings) {
+		return &SortMismatchError{Expected: c.GetOrderings(), Actual: orderings}
+	}
+
+	return nil
+}
+
+// validateElements checks the elements against the orderings they are
+// compared in. Requested are the orderings reported on mismatch.
+func (c *DefaultCursor) validateElements(elements []CursorElement, orderings Orderings, requested Orderings) error {
 	// Do not allow mismatch between number of cursor columns and ordering list.
-	if len(c.elements) != len(orderings) && len(c.elements) != 0 {
-		return fmt.Errorf("cursor column number mismatch")
+	if len(elements) != len(orderings) {
+		return &SortMismatchError{Expected: c.GetOrderings(), Actual: requested}
 	}
 
-	// Validate consistency of ordering and filters. Empty element list is allowed.
-	for i := range c.elements {
-		cond := c.elements[i]
+	// Validate consistency of ordering and filters.
+	for i := range elements {
+		cond := elements[i]
 		orderBy := orderings[i]
 
-		// Verify column names match.
-		if cond.Column != orderBy.Column {
-			return fmt.Errorf("unexpected cursor column '%s'", cond.Column)
+		// Verify operator is acceptable.
+		if !cond.Operator.Valid() {
+			return fmt.Errorf("%w: invalid cursor operator '%s'", ErrInvalidToken, cond.Operator)
 		}
 
-		// Verify operator is acceptable and corresponds to ordering.
-		if !cond.Operator.Valid() {
-			return fmt.Errorf("invalid cursor operator '%s'", cond.Operator)
-		} else if cond.Operator.ForOrdering() != orderBy.Direction {
-			return fmt.Errorf("unexpected cursor operator '%s'", cond.Operator)
+		// Verify column names match and operator corresponds to ordering.
+		if cond.Column != orderBy.Column || cond.Operator.ForOrdering() != orderBy.Direction {
+			return &SortMismatchError{Expected: c.GetOrderings(), Actual: requested}
 		}
 	}
 
 	return nil
 }
 
+// isBackward - implements Cursor.
+func (c *DefaultCursor) isBackward() bool {
+	return c.IsBackward()
+}
+
 var (
 	_ Cursor       = (*DefaultCursor)(nil)
 	_ fmt.Stringer = (*DefaultCursor)(nil)
@@ -209,39 +786,129 @@ var (
 //	}
 type Getters[T any] map[string]func(T) any
 
-// NextPageCursor builds a cursor for the next page of the dataset.
+// NextPageCursor builds a cursor for the next page of the dataset from the
+// last element of the page.
+//
+// The result set is returned in the requested order without the lookahead
+// element, even if the page was fetched backwards.
 func NextPageCursor[T any](
 	initialPager *CursorPager[*DefaultCursor],
 	resultSet []T,
 	getters Getters[T],
 ) ([]T, *DefaultCursor, error) {
+	initialPager = initialPager.forRows(reflect.TypeFor[T]())
 	err := initialPager.validate()
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot build next page cursor: %w", err)
 	}
 
-	if IsLastPage(initialPager, resultSet) {
+	hasNextPage := HasNextPage(initialPager, resultSet)
+	resultSet = TrimResultSet(initialPager, resultSet)
+	if !hasNextPage || len(resultSet) == 0 {
 		return resultSet, nil, nil
 	}
+
+	ret, err := newCursorForRow(initialPager, lo.LastOrEmpty(resultSet), getters, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot build next page cursor: %w", err)
+	}
+
+	return resultSet, ret, nil
+}
+
+// PrevPageCursor builds a cursor for the previous page of the dataset from
+// the first element of the page. Returns nil cursor if the page is the first one.
+// Pass the same fetched result set as to NextPageCursor.
+//
+// The result set is returned in the requested order without the lookahead
+// element, even if the page was fetched backwards.
+func PrevPageCursor[T any](
+	initialPager *CursorPager[*DefaultCursor],
+	resultSet []T,
+	getters Getters[T],
+) ([]T, *DefaultCursor, error) {
+	initialPager = initialPager.forRows(reflect.TypeFor[T]())
+	err := initialPager.validate()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot build previous page cursor: %w", err)
+	}
+
+	hasPrevPage := HasPrevPage(initialPager, resultSet)
 	resultSet = TrimResultSet(initialPager, resultSet)
-	last := lo.LastOrEmpty(resultSet)
+	if !hasPrevPage || len(resultSet) == 0 {
+		return resultSet, nil, nil
+	}
+
+	ret, err := newCursorForRow(initialPager, lo.FirstOrEmpty(resultSet), getters, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot build previous page cursor: %w", err)
+	}
+
+	return resultSet, ret, nil
+}
+
+// CursorForRow builds a cursor pointing to the rows following the row in the
+// order defined by the pager. Use it to build a cursor for every element of
+// the page, e.g. for GraphQL connection edges.
+func CursorForRow[T any](initialPager *CursorPager[*DefaultCursor], row T, getters Getters[T]) (*DefaultCursor, error) {
+	initialPager = initialPager.forRows(reflect.TypeFor[T]())
+	err := initialPager.validate()
+	if err != nil {
+		return nil, fmt.Errorf("cannot build row cursor: %w", err)
+	}
+
+	ret, err := newCursorForRow(initialPager, row, getters, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build row cursor: %w", err)
+	}
 
-	ret := DefaultCursor{elements: nil}
-	for _, orderBy := range initialPager.sort {
+	return ret, nil
+}
+
+// newCursorForRow builds a cursor pointing to the rows after the row (or
+// before it if backward is true) in the order defined by the pager.
+func newCursorForRow[T any](
+	initialPager *CursorPager[*DefaultCursor],
+	row T,
+	getters Getters[T],
+	backward bool,
+) (*DefaultCursor, error) {
+	ret := DefaultCursor{
+		elements:    nil,
+		stopBefore:  initialPager.cursor.GetStopBefore(),
+		sort:        initialPager.orderings(),
+		backward:    backward,
+		codec:       initialPager.codec,
+		fingerprint: initialPager.fingerprint,
+	}
+	ret.issuedAt, ret.expiresAt = initialPager.tokenLifetime()
+
+	for _, orderBy := range initialPager.orderings() {
 		getter, ok := getters[orderBy.Column]
 		if !ok {
-			return nil, nil, fmt.Errorf("cannot find getter for column '%s' met in ordering", orderBy.Column)
+			return nil, &MissingGetterError{Column: orderBy.Column}
+		}
+
+		direction := orderBy.Direction
+		if backward {
+			direction = direction.Invert()
 		}
 
-		value := getter(last)
 		ret.elements = append(ret.elements, CursorElement{
 			Column:   orderBy.Column,
-			Value:    value,
-			Operator: orderBy.Direction.ForOperator(),
+			Value:    getter(row),
+			Operator: direction.ForOperator(),
 		})
 	}
 
-	return resultSet, &ret, nil
+	// Surface encoding errors (e.g. unsupported value types) here rather than
+	// when the token is rendered.
+	_, err := ret.codec.GetCursorCodec().MarshalElements(ret.elements)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
 }
 
 // CursorElement represents a triplet (c v o), where:
@@ -255,10 +922,52 @@ type CursorElement struct {
 	Operator Operator `json:"o"`
 }
 
+// check returns an error if the element cannot be rendered: the column is
+// not a well-formed identifier or the operator is not a comparison.
+func (c *CursorElement) check() error {
+	if !c.Operator.Valid() {
+		return fmt.Errorf("invalid cursor operator '%s'", c.Operator)
+	}
+
+	_, err := parseIdentifier(c.Column)
+
+	return err
+}
+
 func (c *CursorElement) toConjunctWithEqualityCondition() tConjunct {
+	if isNullValue(c.Value) {
+		return tConjunct{Column: c.Column, Operator: operatorIsNull}
+	}
+
 	return tConjunct{
 		Column:   c.Column,
 		Value:    c.Value,
 		Operator: operatorEq,
 	}
 }
+
+// toConjunctsAfter returns the conditions selecting the rows which follow the
+// element value in its column, one condition per disjunct. NULL values
+// following a non-NULL value in the placement of the orderings or, by
+// default, of the dialect are selected with IS NULL, so rows with a NULL sort
+// key are never skipped. The IS NULL condition is omitted for columns known
+// to be NOT NULL, so conditions on them stay index friendly.
+func (c *CursorElement) toConjunctsAfter(dialect Dialect, nulls Nulls, notNull bool) []tConjunct {
+	strict := tConjunct{Column: c.Column, Value: c.Value, Operator: c.Operator}
+	if !c.Operator.Valid() {
+		return []tConjunct{strict}
+	}
+
+	nullsLast := nulls.last(dialect, c.Operator.ForOrdering())
+	switch {
+	case isNullValue(c.Value) && nullsLast:
+		// No values follow NULL.
+		return nil
+	case isNullValue(c.Value):
+		return []tConjunct{{Column: c.Column, Operator: operatorIsNotNull}}
+	case nullsLast && !notNull:
+		return []tConjunct{strict, {Column: c.Column, Operator: operatorIsNull}}
+	default:
+		return []tConjunct{strict}
+	}
+}
@@ -1,6 +1,9 @@
 package gopager
 
 import (
+	"context"
+	"database/sql/driver"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -146,3 +149,377 @@ func Test_DefaultCursor_Stringify_Decode_And_Compare(t *testing.T) {
 
 	require.Equal(t, c2.String(), c.String())
 }
+
+func Test_DecodeCursor_MalformedElements(t *testing.T) {
+	tokens := []string{
+		`[{"c":"1=1) OR (1","v":1,"o":"> 0 OR 1=1 OR 1 <"}]`,
+		`[{"c":"1=1) OR (1","v":1,"o":">"}]`,
+		`[{"c":"id","v":1,"o":"> 0 OR 1=1 OR 1 <"}]`,
+	}
+	for _, token := range tokens {
+		cursor, err := DecodeCursor(_encoder.EncodeToString([]byte(token)))
+		require.ErrorIs(t, err, ErrInvalidToken, token)
+		require.Nil(t, cursor)
+	}
+
+	// Cursors built by hand are checked when applied.
+	db, err := newGORMSQLite()
+	require.NoError(t, err)
+	malformed := NewDefaultCursor(CursorElement{Column: "1=1) OR (1", Value: 1, Operator: OperatorGT})
+	err = malformed.Apply(db.Table("users")).Find(&[]map[string]any{}).Error
+	require.ErrorContains(t, err, "cannot apply cursor")
+}
+
+func Test_PrevPageCursor_Navigation(t *testing.T) {
+	type item struct{ ID int }
+
+	dataset := make([]item, 0, 10)
+	for i := 1; i <= 10; i++ {
+		dataset = append(dataset, item{ID: i})
+	}
+	getters := Getters[item]{"id": func(i item) any { return i.ID }}
+
+	// fetch emulates Paginate + Find over the dataset ordered by id.
+	fetch := func(pager *CursorPager[*DefaultCursor]) []item {
+		direction := pager.GetSort()[0].Direction
+		if pager.GetCursor().IsBackward() {
+			direction = direction.Invert()
+		}
+
+		ret := make([]item, 0, len(dataset))
+		for _, it := range dataset {
+			if elems := pager.GetCursor().GetElements(); len(elems) == 1 {
+				bound := elems[0].Value.(int)
+				if (elems[0].Operator == OperatorGT && it.ID <= bound) || (elems[0].Operator == OperatorLT && it.ID >= bound) {
+					continue
+				}
+			}
+			ret = append(ret, it)
+		}
+		if direction == DirectionDESC {
+			slices.Reverse(ret)
+		}
+
+		return ret[:min(len(ret), pager.GetDatasetLimit())]
+	}
+
+	type page struct {
+		items   []item
+		next    *DefaultCursor
+		prev    *DefaultCursor
+		hasNext bool
+		hasPrev bool
+	}
+	load := func(cursor *DefaultCursor) page {
+		pager := NewCursorPager[*DefaultCursor]().
+			WithLimit(3).
+			WithLookahead().
+			WithCursor(cursor).
+			WithSort(OrderBy{Column: "id", Direction: DirectionASC})
+
+		rs := fetch(pager)
+		items, next, err := NextPageCursor(pager, rs, getters)
+		require.NoError(t, err)
+		prevItems, prev, err := PrevPageCursor(pager, rs, getters)
+		require.NoError(t, err)
+		require.Equal(t, items, prevItems)
+
+		return page{items, next, prev, HasNextPage(pager, rs), HasPrevPage(pager, rs)}
+	}
+
+	p1 := load(nil)
+	require.Equal(t, []item{{1}, {2}, {3}}, p1.items)
+	require.True(t, p1.hasNext)
+	require.False(t, p1.hasPrev)
+	require.Nil(t, p1.prev)
+
+	p2 := load(p1.next)
+	require.Equal(t, []item{{4}, {5}, {6}}, p2.items)
+	require.True(t, p2.prev.IsBackward())
+	require.Equal(t, []CursorElement{{Column: "id", Value: 4, Operator: OperatorLT}}, p2.prev.GetElements())
+
+	p4 := load(load(p2.next).next)
+	require.Equal(t, []item{{10}}, p4.items)
+	require.False(t, p4.hasNext)
+	require.Nil(t, p4.next)
+
+	// Walk backwards from the last page.
+	p3 := load(p4.prev)
+	require.Equal(t, []item{{7}, {8}, {9}}, p3.items)
+	require.True(t, p3.hasNext)
+	require.True(t, p3.hasPrev)
+
+	p2 = load(p3.prev)
+	require.Equal(t, []item{{4}, {5}, {6}}, p2.items)
+
+	p1 = load(p2.prev)
+	require.Equal(t, []item{{1}, {2}, {3}}, p1.items)
+	require.False(t, p1.hasPrev)
+	require.Nil(t, p1.prev)
+	require.Equal(t, []CursorElement{{Column: "id", Value: 3, Operator: OperatorGT}}, p1.next.GetElements())
+	require.False(t, p1.next.IsBackward())
+}
+
+func Test_DefaultCursor_Backward_Token(t *testing.T) {
+	c := NewDefaultCursor(CursorElement{Column: "id", Value: 1, Operator: OperatorLT}).WithBackward(true)
+
+	decoded, err := DecodeCursor(c.String())
+	require.NoError(t, err)
+	require.True(t, decoded.IsBackward())
+
+	require.NoError(t, decoded.validate(Orderings{{Column: "id", Direction: DirectionASC}}))
+	require.Error(t, decoded.validate(Orderings{{Column: "id", Direction: DirectionDESC}}))
+}
+
+func Test_DefaultCursor_NullValues(t *testing.T) {
+	var nilString *string
+
+	tests := []struct {
+		name         string
+		elements     []CursorElement
+		sort         Orderings
+		dialect      Dialect
+		expectedSQL  string
+		expectedArgs []driver.Value
+	}{
+		{
+			name: "explicit nulls last after a value",
+			elements: []CursorElement{
+				{Column: "deleted_at", Value: "2024-01-01", Operator: OperatorLT},
+				{Column: "id", Value: 5, Operator: OperatorGT},
+			},
+			sort: Orderings{
+				{Column: "deleted_at", Direction: DirectionDESC, Nulls: NullsLast},
+				{Column: "id", Direction: DirectionASC},
+			},
+			dialect:      DialectPostgres,
+			expectedSQL:  `(("deleted_at" < $1) OR ("deleted_at" IS NULL) OR ("deleted_at" = $2 AND "id" > $3))`,
+			expectedArgs: []driver.Value{"2024-01-01", "2024-01-01", 5},
+		},
+		{
+			name: "nil value with nulls last",
+			elements: []CursorElement{
+				{Column: "deleted_at", Value: nilString, Operator: OperatorLT},
+				{Column: "id", Value: 5, Operator: OperatorGT},
+			},
+			sort: Orderings{
+				{Column: "deleted_at", Direction: DirectionDESC, Nulls: NullsLast},
+				{Column: "id", Direction: DirectionASC},
+			},
+			dialect:      DialectPostgres,
+			expectedSQL:  `(("deleted_at" IS NULL AND "id" > $1))`,
+			expectedArgs: []driver.Value{5},
+		},
+		{
+			name: "nil value with nulls first",
+			elements: []CursorElement{
+				{Column: "deleted_at", Value: nil, Operator: OperatorGT},
+				{Column: "id", Value: 5, Operator: OperatorGT},
+			},
+			sort: Orderings{
+				{Column: "deleted_at", Direction: DirectionASC, Nulls: NullsFirst},
+				{Column: "id", Direction: DirectionASC},
+			},
+			dialect:      DialectPostgres,
+			expectedSQL:  `(("deleted_at" IS NOT NULL) OR ("deleted_at" IS NULL AND "id" > $1))`,
+			expectedArgs: []driver.Value{5},
+		},
+		{
+			name: "nil value with mysql default placement",
+			elements: []CursorElement{
+				{Column: "deleted_at", Value: nil, Operator: OperatorGT},
+				{Column: "id", Value: 5, Operator: OperatorGT},
+			},
+			dialect:      DialectMySQL,
+			expectedSQL:  "((`deleted_at` IS NOT NULL) OR (`deleted_at` IS NULL AND `id` > ?))",
+			expectedArgs: []driver.Value{5},
+		},
+		{
+			name: "nil value with postgres default placement",
+			elements: []CursorElement{
+				{Column: "deleted_at", Value: nil, Operator: OperatorGT},
+				{Column: "id", Value: 5, Operator: OperatorGT},
+			},
+			dialect:      DialectPostgres,
+			expectedSQL:  `(("deleted_at" IS NULL AND "id" > $1))`,
+			expectedArgs: []driver.Value{5},
+		},
+		{
+			name:        "nothing follows",
+			elements:    []CursorElement{{Column: "deleted_at", Value: nil, Operator: OperatorGT}},
+			dialect:     DialectPostgres,
+			expectedSQL: "1 = 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := newSQLArgs(tt.dialect, 1)
+			where, _ := NewDefaultCursor(tt.elements...).renderSQL(args, cursorOptions{rowValues: true, sort: tt.sort})
+			require.Equal(t, tt.expectedSQL, where)
+			require.Equal(t, tt.expectedArgs, args.values)
+		})
+	}
+}
+
+func Test_DefaultCursor_NullValues_SQLite(t *testing.T) {
+	type task struct {
+		ID       int
+		Priority *int
+	}
+
+	db, err := newGORMSQLite()
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&task{}))
+	for i, priority := range []*int{nil, new(int), nil, nil, new(int)} {
+		require.NoError(t, db.Create(&task{ID: i + 1, Priority: priority}).Error)
+	}
+	require.NoError(t, db.Model(&task{}).Where("id = ?", 5).Update("priority", 7).Error)
+
+	getters := Getters[task]{
+		"priority": func(t task) any { return t.Priority },
+		"id":       func(t task) any { return t.ID },
+	}
+
+	// NULL values are the smallest in SQLite: they are last in DESC order.
+	tests := []struct {
+		direction Direction
+		expected  []int
+	}{
+		{DirectionASC, []int{1, 3, 4, 2, 5}},
+		{DirectionDESC, []int{5, 2, 4, 3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.direction), func(t *testing.T) {
+			pager := NewCursorPager[*DefaultCursor]().WithLimit(2).WithSort(
+				OrderBy{Column: "priority", Direction: tt.direction},
+				OrderBy{Column: "id", Direction: tt.direction},
+			)
+
+			var ids []int
+			for row, err := range All(context.Background(), db.Model(&task{}), pager, getters) {
+				require.NoError(t, err)
+				ids = append(ids, row.ID)
+			}
+			require.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func Test_DefaultCursor_GetOrderings(t *testing.T) {
+	cursor := NewDefaultCursor(
+		CursorElement{Column: "created_at", Value: "2024-01-01", Operator: OperatorLT},
+		CursorElement{Column: "id", Value: 5, Operator: OperatorGT},
+	)
+	expected := Orderings{
+		{Column: "created_at", Direction: DirectionDESC},
+		{Column: "id", Direction: DirectionASC},
+	}
+
+	require.Equal(t, expected, cursor.GetOrderings())
+	require.Equal(t, expected, cursor.Reversed().GetOrderings())
+	require.Empty(t, (*DefaultCursor)(nil).GetOrderings())
+
+	err := cursor.validate(Orderings{{Column: "id", Direction: DirectionASC}})
+	var mismatch *SortMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	require.ErrorIs(t, err, ErrSortMismatch)
+	require.Equal(t, expected, mismatch.Expected)
+	require.Equal(t, Orderings{{Column: "id", Direction: DirectionASC}}, mismatch.Actual)
+}
+
+func Test_DecodeCursorPager_RestoresOrderings(t *testing.T) {
+	type item struct {
+		ID        int
+		CreatedAt string
+		Email     string
+	}
+
+	sort := Orderings{
+		{Column: "created_at", Direction: DirectionDESC, Nulls: NullsLast},
+		{Column: "email", Direction: DirectionASC, Expr: "LOWER(email)", Collation: "C"},
+		{Column: "id", Direction: DirectionASC},
+	}
+	getters := Getters[item]{
+		"created_at": func(i item) any { return i.CreatedAt },
+		"email":      func(i item) any { return i.Email },
+		"id":         func(i item) any { return i.ID },
+	}
+	signer := newTestSigner(t, "v1", "v1")
+	mapping := ExprMapping{"email": "LOWER(email)"}
+
+	for _, codec := range []*TokenCodec{
+		NewTokenCodec().WithSigner(signer).WithExprMappings(mapping),
+		NewTokenCodec().WithSigner(signer).WithExprMappings(mapping).WithCursorCodec(BinaryCursorCodec{}),
+	} {
+		pager := NewCursorPager[*DefaultCursor]().WithLimit(1).WithTokenCodec(codec).WithSort(sort...)
+		_, next, err := NextPageCursor(pager, []item{{ID: 7, CreatedAt: "2024-01-01", Email: "a@example.com"}}, getters)
+		require.NoError(t, err)
+		_, prev, err := PrevPageCursor(pager.WithCursor(next), []item{{ID: 8, CreatedAt: "2023-12-31", Email: "b@example.com"}}, getters)
+		require.NoError(t, err)
+
+		for _, token := range []string{next.String(), prev.String()} {
+			restored, err := codec.DecodeCursorPager(1, token)
+			require.NoError(t, err)
+			require.Equal(t, sort, restored.GetSort())
+			require.NoError(t, restored.validate())
+
+			// A prefix of the orderings may be completed later.
+			_, err = codec.DecodeCursorPager(1, token, sort[0])
+			require.NoError(t, err)
+
+			_, err = codec.DecodeCursorPager(1, token, OrderBy{Column: "created_at", Direction: DirectionASC})
+			var mismatch *SortMismatchError
+			require.ErrorAs(t, err, &mismatch)
+			require.Equal(t, sort, mismatch.Expected)
+
+			// Placement of NULL values, expressions and collations are compared too.
+			for _, changed := range []OrderBy{
+				{Column: "created_at", Direction: DirectionDESC, Nulls: NullsFirst},
+				{Column: "created_at", Direction: DirectionDESC},
+				{Column: "created_at", Direction: DirectionDESC, Nulls: NullsLast, Expr: "DATE(created_at)"},
+				{Column: "created_at", Direction: DirectionDESC, Nulls: NullsLast, Collation: "C"},
+			} {
+				_, err = codec.DecodeCursorPager(1, token, changed)
+				require.ErrorIs(t, err, ErrSortMismatch, "%+v", changed)
+
+				full := slices.Clone(sort)
+				full[0] = changed
+				decoded, err := codec.DecodeCursor(token)
+				require.NoError(t, err)
+				err = NewCursorPager[*DefaultCursor]().WithLimit(1).WithCursor(decoded).WithSort(full...).validate()
+				require.ErrorIs(t, err, ErrSortMismatch, "%+v", changed)
+			}
+
+			// Expressions are resolved from the aliases on the server.
+			_, err = NewTokenCodec().WithSigner(signer).DecodeCursorPager(1, token)
+			require.ErrorIs(t, err, ErrInvalidToken)
+
+			changedExpr := NewTokenCodec().WithSigner(signer).WithExprMappings(ExprMapping{"email": "LOWER(TRIM(email))"})
+			restored, err = changedExpr.DecodeCursorPager(1, token)
+			require.NoError(t, err)
+			require.Equal(t, OrderByExpr("LOWER(TRIM(email))"), restored.GetSort()[1].Expr)
+		}
+
+		// Cursors built by hand keep no orderings: they are not restored.
+		handmade := NewDefaultCursor(CursorElement{Column: "id", Value: 1, Operator: OperatorGT}).WithTokenCodec(codec)
+		_, err = codec.DecodeCursorPager(1, handmade.String())
+		require.ErrorIs(t, err, ErrSortMismatch)
+		restored, err := codec.DecodeCursorPager(1, handmade.String(), OrderBy{Column: "id", Direction: DirectionASC, Nulls: NullsLast})
+		require.NoError(t, err)
+		require.NoError(t, restored.validate())
+	}
+
+	// Expressions are never exposed to clients.
+	pager := NewCursorPager[*DefaultCursor]().WithLimit(1).WithSort(sort...)
+	_, next, err := NextPageCursor(pager, []item{{ID: 7, CreatedAt: "2024-01-01", Email: "a@example.com"}}, getters)
+	require.NoError(t, err)
+	data, err := _encoder.DecodeString(next.String())
+	require.NoError(t, err)
+	require.Contains(t, string(data), "email")
+	require.NotContains(t, string(data), "LOWER")
+
+	// Columns of plain tokens are controlled by clients.
+	plain, err := DecodeCursorPager(1, NewDefaultCursor(CursorElement{Column: "id", Value: 1, Operator: OperatorGT}).String())
+	require.NoError(t, err)
+	require.Empty(t, plain.GetSort())
+}
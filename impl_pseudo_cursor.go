@@ -3,6 +3,7 @@ package gopager
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -13,7 +14,10 @@ import (
 // It implements Cursor and generates a token based on the last offset within
 // the dataset.
 type PseudoCursor struct {
-	offset int
+	offset    int
+	codec     *TokenCodec
+	issuedAt  time.Time
+	expiresAt time.Time
 }
 
 func NewPseudoCursor(offset int) *PseudoCursor {
@@ -23,24 +27,9 @@ func NewPseudoCursor(offset int) *PseudoCursor {
 }
 
 // DecodePseudoCursor attempts to parse a base64-encoded string into *PseudoCursor.
+// Use TokenCodec.DecodePseudoCursor to decode signed tokens.
 func DecodePseudoCursor(b64String string) (*PseudoCursor, error) {
-	if len(b64String) == 0 {
-		return nil, nil
-	}
-
-	offsetBytes, err := _encoder.DecodeString(b64String)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode base64 encoded pseudo cursor: %w", err)
-	}
-
-	offset, err := strconv.Atoi(string(offsetBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode pseudo cursor offset value: %w", err)
-	}
-
-	return &PseudoCursor{
-		offset: offset,
-	}, nil
+	return (*TokenCodec)(nil).DecodePseudoCursor(b64String)
 }
 
 // ToSQL - implements Cursor. Returns the string form of the numeric offset value.
@@ -52,13 +41,48 @@ func (p *PseudoCursor) ToSQL() string {
 	return strconv.Itoa(p.offset)
 }
 
-// String - implements fmt.Stringer.
+// ToDialectSQL returns the clause limiting the result set to limit records
+// starting from the offset, rendered for the dialect. Limit equal to NoLimit
+// means no limit.
+//
+// Usage:
+//
+//	query := fmt.Sprintf("SELECT * FROM table ORDER BY id %s", p.ToDialectSQL(gopager.DialectSQLServer, 10))
+func (p *PseudoCursor) ToDialectSQL(dialect Dialect, limit int) string {
+	return dialect.LimitOffset(limit, p.GetOffset())
+}
+
+// String - implements fmt.Stringer. Intended for logging only, use Encode
+// to issue tokens. If the cursor cannot be encoded, returns a description of
+// the error, which is never a valid token: an empty string always means an
+// empty cursor.
 func (p *PseudoCursor) String() string {
+	token, err := p.Encode()
+	if err != nil {
+		return fmt.Sprintf("%%!s(*gopager.PseudoCursor: %v)", err)
+	}
+
+	return token
+}
+
+// Encode converts the cursor into a token using the cursor TokenCodec.
+func (p *PseudoCursor) Encode() (string, error) {
 	if p == nil || p.offset == 0 {
-		return ""
+		return "", nil
+	}
+
+	cursorCodec := p.codec.GetCursorCodec()
+	body, err := cursorCodec.MarshalOffset(p.offset)
+	if err != nil {
+		return "", err
 	}
 
-	return _encoder.EncodeToString([]byte(strconv.Itoa(p.offset)))
+	return p.codec.encode(tokenEnvelope{
+		format:    cursorCodec.Format(),
+		issuedAt:  p.issuedAt,
+		expiresAt: p.expiresAt,
+		body:      body,
+	})
 }
 
 // IsEmpty - implements Cursor.
@@ -71,6 +95,16 @@ func (p *PseudoCursor) Apply(db *gorm.DB) *gorm.DB {
 	return db.Offset(p.GetOffset())
 }
 
+// apply - implements Cursor. The offset is not affected by the options.
+func (p *PseudoCursor) apply(db *gorm.DB, _ cursorOptions) *gorm.DB {
+	return p.Apply(db)
+}
+
+// renderSQL - implements Cursor. The dataset is not filtered, only offset.
+func (p *PseudoCursor) renderSQL(args *sqlArgs, _ cursorOptions) (string, int) {
+	return args.dialect.True(), p.GetOffset()
+}
+
 // GetOffset returns the numeric offset value.
 func (p *PseudoCursor) GetOffset() int {
 	if p != nil {
@@ -91,11 +125,61 @@ func (p *PseudoCursor) WithOffset(offset int) *PseudoCursor {
 	return p
 }
 
+// GetTokenCodec returns the codec used to encode the cursor.
+func (p *PseudoCursor) GetTokenCodec() *TokenCodec {
+	if p == nil {
+		return nil
+	}
+
+	return p.codec
+}
+
+// WithTokenCodec sets the codec used to encode the cursor.
+func (p *PseudoCursor) WithTokenCodec(codec *TokenCodec) *PseudoCursor {
+	if p == nil {
+		p = new(PseudoCursor)
+	}
+
+	p.codec = codec
+
+	return p
+}
+
+// GetIssuedAt returns the time the cursor was issued at. Zero time means
+// the issue time is unknown.
+func (p *PseudoCursor) GetIssuedAt() time.Time {
+	if p == nil {
+		return time.Time{}
+	}
+
+	return p.issuedAt
+}
+
+// GetExpiresAt returns the time after which the cursor token is rejected.
+// Zero time means the token never expires.
+func (p *PseudoCursor) GetExpiresAt() time.Time {
+	if p == nil {
+		return time.Time{}
+	}
+
+	return p.expiresAt
+}
+
 // validate - implements Cursor.
 func (p *PseudoCursor) validate(_ Orderings) error {
 	return nil
 }
 
+// isBackward - implements Cursor.
+func (p *PseudoCursor) isBackward() bool {
+	return false
+}
+
+// isStart - implements Cursor.
+func (p *PseudoCursor) isStart() bool {
+	return p.IsEmpty()
+}
+
 var (
 	_ Cursor       = (*PseudoCursor)(nil)
 	_ fmt.Stringer = (*PseudoCursor)(nil)
@@ -112,13 +196,15 @@ func NextPagePseudoCursor[T any](
 	}
 
 	if IsLastPage(initialPager, resultSet) {
-		return resultSet, nil, nil
+		return TrimResultSet(initialPager, resultSet), nil, nil
 	}
 	resultSet = TrimResultSet(initialPager, resultSet)
 
-	return resultSet,
-		&PseudoCursor{
-			offset: initialPager.cursor.GetOffset() + len(resultSet),
-		},
-		nil
+	ret := PseudoCursor{
+		offset: initialPager.cursor.GetOffset() + len(resultSet),
+		codec:  initialPager.codec,
+	}
+	ret.issuedAt, ret.expiresAt = initialPager.tokenLifetime()
+
+	return resultSet, &ret, nil
 }
@@ -0,0 +1,219 @@
+package gopager
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// Pages iterates over the pages of the dataset queried by db, starting from
+// the page of the pager, until the last page. An unlimited pager yields a
+// single page, an empty page ends the iteration and is skipped. The
+// context is checked between pages and passed to the queries. The pager is
+// not modified.
+//
+// Getters build cursors of *DefaultCursor pagers and are ignored for
+// *PseudoCursor pagers. An error ends the iteration.
+//
+// Usage:
+//
+//	for page, err := range gopager.Pages(ctx, db.Model(&User{}), pager, getters) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func Pages[T any, CursorType Cursor](
+	ctx context.Context,
+	db *gorm.DB,
+	initialPager *CursorPager[CursorType],
+	getters Getters[T],
+) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		pager := new(CursorPager[CursorType])
+		if initialPager != nil {
+			*pager = *initialPager
+		}
+
+		for {
+			err := ctx.Err()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			page, next, err := fetchPage(ctx, db, pager, getters)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(page) != 0 && !yield(page, nil) {
+				return
+			}
+			if next == nil {
+				return
+			}
+
+			pager.cursor = *next
+		}
+	}
+}
+
+// All iterates over the records of the dataset queried by db page by page,
+// see Pages.
+//
+// Usage:
+//
+//	for user, err := range gopager.All(ctx, db.Model(&User{}), pager, getters) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func All[T any, CursorType Cursor](
+	ctx context.Context,
+	db *gorm.DB,
+	initialPager *CursorPager[CursorType],
+	getters Getters[T],
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page, err := range Pages(ctx, db, initialPager, getters) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// PrefetchPages works like Pages, but fetches up to depth next pages in the
+// background while the caller processes the current one. Pages are fetched
+// one after another: the cursor of the next page is built from the last row
+// of the previous one. At most depth pages wait for the caller, depth below 1
+// means 1.
+//
+// Pages and errors are yielded in the order they are fetched: an error is
+// yielded after all pages fetched before it and ends the iteration. Breaking
+// the loop cancels the background query and waits for it to stop.
+//
+// Usage:
+//
+//	for page, err := range gopager.PrefetchPages(ctx, db.Model(&User{}), pager, getters, 2) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func PrefetchPages[T any, CursorType Cursor](
+	ctx context.Context,
+	db *gorm.DB,
+	initialPager *CursorPager[CursorType],
+	getters Getters[T],
+	depth int,
+) iter.Seq2[[]T, error] {
+	type result struct {
+		page []T
+		err  error
+	}
+
+	return func(yield func([]T, error) bool) {
+		// The page blocked on sending is fetched ahead too.
+		results := make(chan result, max(depth, 1)-1)
+		// stop is closed when the caller stops the iteration. Unlike the
+		// cancellation of ctx, it drops the pages which were not yielded yet.
+		stop := make(chan struct{})
+		fetchCtx, cancel := context.WithCancel(ctx)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(results)
+
+			for page, err := range Pages(fetchCtx, db, initialPager, getters) {
+				select {
+				case results <- result{page: page, err: err}:
+				case <-stop:
+					return
+				}
+			}
+		}()
+		defer func() {
+			close(stop)
+			cancel()
+			wg.Wait()
+		}()
+
+		for r := range results {
+			if !yield(r.page, r.err) || r.err != nil {
+				return
+			}
+		}
+	}
+}
+
+// fetchPage fetches the page of the pager and builds the cursor of the next
+// page. The cursor is nil on the last page.
+func fetchPage[T any, CursorType Cursor](
+	ctx context.Context,
+	db *gorm.DB,
+	pager *CursorPager[CursorType],
+	getters Getters[T],
+) ([]T, *CursorType, error) {
+	paged, err := pager.Paginate(db.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resultSet []T
+	err = paged.Find(&resultSet).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot fetch page: %w", err)
+	}
+
+	return nextPage(pager, resultSet, getters)
+}
+
+// nextPage trims the result set and builds the cursor of the next page with
+// the function matching the cursor type. The cursor is nil on the last page:
+// an unlimited pager fetches the whole dataset at once and an empty page
+// means the end of the dataset.
+func nextPage[T any, CursorType Cursor](
+	pager *CursorPager[CursorType],
+	resultSet []T,
+	getters Getters[T],
+) ([]T, *CursorType, error) {
+	var (
+		next    any
+		hasNext bool
+		err     error
+	)
+	switch p := any(pager).(type) {
+	case *CursorPager[*DefaultCursor]:
+		var cursor *DefaultCursor
+		resultSet, cursor, err = NextPageCursor(p, resultSet, getters)
+		next, hasNext = cursor, cursor != nil
+	case *CursorPager[*PseudoCursor]:
+		var cursor *PseudoCursor
+		resultSet, cursor, err = NextPagePseudoCursor(p, resultSet)
+		next, hasNext = cursor, cursor != nil
+	default:
+		return nil, nil, fmt.Errorf("cannot iterate over pages of %T", pager)
+	}
+	if err != nil || !hasNext || pager.IsUnlimited() || len(resultSet) == 0 {
+		return resultSet, nil, err
+	}
+
+	ret := next.(CursorType)
+	return resultSet, &ret, nil
+}
@@ -0,0 +1,224 @@
+package gopager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func Test_Pages(t *testing.T) {
+	db := newCountTestDB(t)
+	getters := Getters[testCountUser]{"id": func(u testCountUser) any { return u.ID }}
+	sort := OrderBy{Column: "id", Direction: DirectionASC}
+
+	collectIDs := func(t *testing.T, seq func(yield func([]testCountUser, error) bool)) [][]int {
+		var ret [][]int
+		for page, err := range seq {
+			require.NoError(t, err)
+			var ids []int
+			for _, u := range page {
+				ids = append(ids, u.ID)
+			}
+			ret = append(ret, ids)
+		}
+		return ret
+	}
+
+	t.Run("default cursor", func(t *testing.T) {
+		pager := NewCursorPager[*DefaultCursor]().WithLimit(3).WithLookahead().WithSort(sort)
+		got := collectIDs(t, Pages(context.Background(), db.Model(&testCountUser{}), pager, getters))
+		require.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {10}}, got)
+		// The pager is not modified.
+		require.Nil(t, pager.GetCursor())
+	})
+
+	t.Run("without lookahead", func(t *testing.T) {
+		pager := NewCursorPager[*DefaultCursor]().WithLimit(5).WithSort(sort)
+		got := collectIDs(t, Pages(context.Background(), db.Model(&testCountUser{}), pager, getters))
+		require.Equal(t, [][]int{{1, 2, 3, 4, 5}, {6, 7, 8, 9, 10}}, got)
+	})
+
+	t.Run("pseudo cursor", func(t *testing.T) {
+		pager := NewCursorPager[*PseudoCursor]().WithLimit(4).WithLookahead().WithSort(sort).WithCursor(NewPseudoCursor(2))
+		got := collectIDs(t, Pages[testCountUser](context.Background(), db.Model(&testCountUser{}), pager, nil))
+		require.Equal(t, [][]int{{3, 4, 5, 6}, {7, 8, 9, 10}}, got)
+	})
+
+	t.Run("unlimited pseudo cursor", func(t *testing.T) {
+		pager := NewCursorPager[*PseudoCursor]().WithUnlimited().WithSort(sort)
+		got := collectIDs(t, Pages[testCountUser](context.Background(), db.Model(&testCountUser{}), pager, nil))
+		require.Equal(t, [][]int{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}, got)
+	})
+
+	t.Run("pseudo cursor past the end", func(t *testing.T) {
+		pager := NewCursorPager[*PseudoCursor]().WithUnlimited().WithSort(sort).WithCursor(NewPseudoCursor(20))
+		got := collectIDs(t, Pages[testCountUser](context.Background(), db.Model(&testCountUser{}), pager, nil))
+		require.Empty(t, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		pager := NewCursorPager[*DefaultCursor]().WithLimit(3).WithLookahead().WithSort(sort)
+		var errs int
+		for page, err := range Pages(context.Background(), db.Model(&testCountUser{}), pager, Getters[testCountUser]{}) {
+			require.Nil(t, page)
+			require.ErrorIs(t, err, ErrMissingGetter)
+			errs++
+		}
+		require.Equal(t, 1, errs)
+	})
+}
+
+func Test_All(t *testing.T) {
+	db := newCountTestDB(t)
+	getters := Getters[testCountUser]{"id": func(u testCountUser) any { return u.ID }}
+	pager := NewCursorPager[*DefaultCursor]().WithLimit(3).WithLookahead().
+		WithSort(OrderBy{Column: "id", Direction: DirectionDESC})
+
+	var ids []int
+	for u, err := range All(context.Background(), db.Model(&testCountUser{}).Where("active = ?", true), pager, getters) {
+		require.NoError(t, err)
+		ids = append(ids, u.ID)
+	}
+	require.Equal(t, []int{10, 8, 6, 4, 2}, ids)
+
+	t.Run("break", func(t *testing.T) {
+		var queries int
+		counted := db.Session(&gorm.Session{NewDB: true})
+		require.NoError(t, counted.Callback().Query().Before("gorm:query").Register("test:count", func(*gorm.DB) {
+			queries++
+		}))
+		t.Cleanup(func() { _ = counted.Callback().Query().Remove("test:count") })
+
+		ids = nil
+		for u, err := range All(context.Background(), counted.Model(&testCountUser{}), pager, getters) {
+			require.NoError(t, err)
+			ids = append(ids, u.ID)
+			if len(ids) == 4 {
+				break
+			}
+		}
+		require.Equal(t, []int{10, 9, 8, 7}, ids)
+		require.Equal(t, 2, queries)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ids = nil
+		var err error
+		for u, iterErr := range All(ctx, db.Model(&testCountUser{}), pager, getters) {
+			if iterErr != nil {
+				err = iterErr
+				break
+			}
+			ids = append(ids, u.ID)
+			cancel()
+		}
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, []int{10, 9, 8}, ids)
+	})
+}
+
+func Test_PrefetchPages(t *testing.T) {
+	db := newCountTestDB(t)
+	getters := Getters[testCountUser]{"id": func(u testCountUser) any { return u.ID }}
+	pager := NewCursorPager[*DefaultCursor]().WithLimit(2).WithLookahead().
+		WithSort(OrderBy{Column: "id", Direction: DirectionASC})
+
+	// counted counts the page queries and fails the query with the number in failAt.
+	var queries, failAt atomic.Int32
+	errQuery := errors.New("query failed")
+	counted := db.Session(&gorm.Session{NewDB: true})
+	require.NoError(t, counted.Callback().Query().Before("gorm:query").Register("test:prefetch", func(tx *gorm.DB) {
+		if queries.Add(1) == failAt.Load() {
+			_ = tx.AddError(errQuery)
+		}
+	}))
+	t.Cleanup(func() { _ = counted.Callback().Query().Remove("test:prefetch") })
+	reset := func(fail int32) {
+		queries.Store(0)
+		failAt.Store(fail)
+	}
+
+	for _, depth := range []int{0, 1, 2, 10} {
+		t.Run(fmt.Sprintf("depth %d", depth), func(t *testing.T) {
+			reset(0)
+			var got [][]int
+			for page, err := range PrefetchPages(context.Background(), counted.Model(&testCountUser{}), pager, getters, depth) {
+				require.NoError(t, err)
+				if len(got) == 0 {
+					// The next pages are fetched while the first one is processed.
+					ahead := int32(min(max(depth, 1), 4))
+					require.Eventually(t, func() bool { return queries.Load() == 1+ahead }, time.Second, time.Millisecond)
+					time.Sleep(10 * time.Millisecond)
+					require.Equal(t, 1+ahead, queries.Load())
+				}
+
+				var ids []int
+				for _, u := range page {
+					ids = append(ids, u.ID)
+				}
+				got = append(got, ids)
+			}
+			require.Equal(t, [][]int{{1, 2}, {3, 4}, {5, 6}, {7, 8}, {9, 10}}, got)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		for range 20 {
+			reset(3)
+			var (
+				pages int
+				err   error
+			)
+			for _, iterErr := range PrefetchPages(context.Background(), counted.Model(&testCountUser{}), pager, getters, 3) {
+				if iterErr != nil {
+					err = iterErr
+					continue
+				}
+				pages++
+			}
+			require.ErrorIs(t, err, errQuery)
+			require.Equal(t, 2, pages)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		reset(0)
+		for range PrefetchPages(context.Background(), counted.Model(&testCountUser{}), pager, getters, 2) {
+			break
+		}
+		// The background fetch is stopped when the loop ends.
+		stopped := queries.Load()
+		time.Sleep(10 * time.Millisecond)
+		require.Equal(t, stopped, queries.Load())
+		require.LessOrEqual(t, stopped, int32(3))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		reset(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var (
+			pages int
+			err   error
+		)
+		for _, iterErr := range PrefetchPages(ctx, counted.Model(&testCountUser{}), pager, getters, 1) {
+			if iterErr != nil {
+				err = iterErr
+				continue
+			}
+			pages++
+			cancel()
+		}
+		require.ErrorIs(t, err, context.Canceled)
+		require.Less(t, pages, 5)
+	})
+}
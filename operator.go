@@ -28,4 +28,29 @@ const (
 	// operatorEq is the equality operator. It is private because we use it
 	// ONLY while building filtering conditions.
 	operatorEq Operator = "="
+	// operatorIsNull and operatorIsNotNull compare a column with NULL. They
+	// take no value and are used ONLY while building filtering conditions.
+	operatorIsNull    Operator = "IS NULL"
+	operatorIsNotNull Operator = "IS NOT NULL"
+	// operatorLE and operatorGE include the row at a stop before bound. They
+	// are used ONLY while building filtering conditions.
+	operatorLE Operator = "<="
+	operatorGE Operator = ">="
 )
+
+// isNullCheck returns true if the operator takes no value.
+func (o Operator) isNullCheck() bool {
+	return o == operatorIsNull || o == operatorIsNotNull
+}
+
+// orEqual returns the comparison including equal values.
+func (o Operator) orEqual() Operator {
+	switch o {
+	case OperatorLT:
+		return operatorLE
+	case OperatorGT:
+		return operatorGE
+	default:
+		return o
+	}
+}
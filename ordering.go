@@ -1,6 +1,7 @@
 package gopager
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -9,6 +10,36 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrInvalidSort is returned when orderings are malformed, e.g. have an
+	// unknown direction or an invalid column name.
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrEmptySort is returned when a pager has no orderings, e.g. WithSort
+	// was called with no arguments. It is a setup error, unlike
+	// ErrInvalidSort.
+	ErrEmptySort = errors.New("empty ordering list")
+	// ErrUnknownSortAlias is returned by ParseSort when an alias is not
+	// found in the mappings. Use errors.As with *UnknownSortAliasError to get
+	// the closest known alias.
+	ErrUnknownSortAlias = errors.New("unknown sort alias")
+)
+
+// UnknownSortAliasError describes a sort alias missing in the mappings.
+type UnknownSortAliasError struct {
+	// Alias is the requested alias.
+	Alias ColumnAlias
+	// Suggestion is the closest known alias.
+	Suggestion ColumnAlias
+}
+
+func (e *UnknownSortAliasError) Error() string {
+	return fmt.Sprintf("%s '%s'. closest: '%s'", ErrUnknownSortAlias, e.Alias, e.Suggestion)
+}
+
+func (e *UnknownSortAliasError) Unwrap() error {
+	return ErrUnknownSortAlias
+}
+
 // Direction defines the sort direction for the requested dataset.
 type Direction string
 
@@ -21,6 +52,18 @@ func (o Direction) Valid() bool {
 	return o == DirectionASC || o == DirectionDESC
 }
 
+// Invert returns the opposite direction.
+func (o Direction) Invert() Direction {
+	switch o {
+	case DirectionASC:
+		return DirectionDESC
+	case DirectionDESC:
+		return DirectionASC
+	default:
+		panic(fmt.Errorf("cannot invert direction '%s'", o))
+	}
+}
+
 func (o Direction) ForOperator() Operator {
 	switch o {
 	case DirectionASC:
@@ -32,11 +75,74 @@ func (o Direction) ForOperator() Operator {
 	}
 }
 
+// Nulls defines the placement of NULL values in the requested dataset.
+type Nulls string
+
+const (
+	// NullsDBDefault keeps the placement of the database: NULL values are the
+	// largest in PostgreSQL and Oracle and the smallest in MySQL, SQLite and
+	// SQL Server.
+	NullsDBDefault Nulls = ""
+	NullsFirst     Nulls = "FIRST"
+	NullsLast      Nulls = "LAST"
+)
+
+func (n Nulls) Valid() bool {
+	return n == NullsDBDefault || n == NullsFirst || n == NullsLast
+}
+
+// Invert returns the opposite placement. NullsDBDefault is kept as is: the
+// database places NULL values according to the inverted direction.
+func (n Nulls) Invert() Nulls {
+	switch n {
+	case NullsFirst:
+		return NullsLast
+	case NullsLast:
+		return NullsFirst
+	default:
+		return n
+	}
+}
+
+// last reports whether NULL values follow non-NULL values when the dataset
+// is read in the direction.
+func (n Nulls) last(dialect Dialect, direction Direction) bool {
+	switch n {
+	case NullsFirst:
+		return false
+	case NullsLast:
+		return true
+	default:
+		return dialect.NullsLargest() == (direction == DirectionASC)
+	}
+}
+
+// OrderByExpr is a trusted SQL expression used as a sort key, e.g.
+// LOWER(email), data->>'score' or COALESCE(updated_at, created_at). It is
+// rendered into queries as is.
+//
+// IMPORTANT:
+// Expressions MUST come from server-side definitions only, e.g. ExprMapping
+// values. NEVER build them from user input.
+type OrderByExpr string
+
 type (
 	Orderings []OrderBy
 	OrderBy   struct {
+		// Column is the sort column. If Expr is set, Column is the stable alias
+		// of the expression: it is stored in tokens and used to look up Getters.
 		Column    string
 		Direction Direction
+		// Expr is the trusted expression sorted by instead of Column.
+		Expr OrderByExpr
+		// Nulls is the placement of NULL values. NullsDBDefault keeps the
+		// placement of the dialect. Keyset conditions select NULL values
+		// following the cursor value in either case.
+		Nulls Nulls
+		// Collation is the collation applied both to ORDER BY and to the
+		// keyset conditions, e.g. "C" or "NOCASE". Empty means the collation
+		// of the column.
+		Collation string
 	}
 
 	ColumnAlias = string
@@ -45,31 +151,96 @@ type (
 	// Use it when bare column names could cause an "ambiguous column name" error.
 	// Key is an external alias, value is an internal column name.
 	ColumnMapping = map[ColumnAlias]string
-)
 
-var _availableColumnNameSymbols = append([]rune("_.'`\""), lo.AlphanumericCharset...)
+	// ExprMapping maps external column aliases to trusted sort expressions.
+	// Key is an external alias, it is also stored in tokens.
+	ExprMapping = map[ColumnAlias]OrderByExpr
+)
 
 func (o OrderBy) validate() error {
 	if !o.Direction.Valid() {
 		return fmt.Errorf("invalid ordering direction '%s'", o.Direction)
 	}
+	if !o.Nulls.Valid() {
+		return fmt.Errorf("invalid ordering nulls placement '%s'", o.Nulls)
+	}
 
-	// Guard against SQL injection by restricting allowed characters in column names.
-	if !lo.Every(_availableColumnNameSymbols, []rune(o.Column)) {
-		return fmt.Errorf("ordering column name contains forbidden symbols '%s'", o.Column)
+	// Guard against SQL injection by accepting only well-formed identifiers.
+	// Expressions are trusted, but their aliases end up in tokens.
+	_, err := parseIdentifier(o.Column)
+	if err != nil {
+		return fmt.Errorf("invalid ordering column: %w", err)
+	}
+	if o.Expr != "" && strings.TrimSpace(string(o.Expr)) == "" {
+		return fmt.Errorf("empty ordering expression of column '%s'", o.Column)
+	}
+	if o.Collation != "" && !isCollationName(o.Collation) {
+		return fmt.Errorf("invalid ordering collation '%s'", o.Collation)
 	}
 
 	return nil
 }
 
+// validateDialect checks that the collation is supported by the dialect.
+func (o OrderBy) validateDialect(dialect Dialect) error {
+	if o.Collation == "" {
+		return nil
+	}
+
+	_, err := dialect.Collation(o.Collation)
+	if err != nil {
+		return fmt.Errorf("invalid ordering collation of column '%s': %w", o.Column, err)
+	}
+
+	return nil
+}
+
+// comparisonKey returns the sort key with the collation applied, used both
+// in ORDER BY and in the keyset conditions.
+func (o OrderBy) comparisonKey(dialect Dialect) string {
+	if o.Collation == "" {
+		return o.sqlKey(dialect)
+	}
+
+	collation, err := dialect.Collation(o.Collation)
+	if err != nil {
+		// Orderings are validated for the dialect before rendering. Keep the
+		// name in the identifier context anyway.
+		collation = dialect.QuoteIdentifier(o.Collation)
+	}
+
+	return fmt.Sprintf("%s COLLATE %s", o.sqlKey(dialect), collation)
+}
+
+// sqlKey returns the sort key rendered for the dialect: the expression in
+// parentheses or the quoted column.
+func (o OrderBy) sqlKey(dialect Dialect) string {
+	if o.Expr != "" {
+		return o.Expr.sql()
+	}
+
+	return quoteColumn(dialect, o.Column)
+}
+
+// sql returns the expression in parentheses, so it is safe to combine with
+// operators.
+func (e OrderByExpr) sql() string {
+	return "(" + string(e) + ")"
+}
+
 // ToSQLSlice converts Orderings to a slice of strings in the form
-// "<order_column> <order_direction>" suitable for SQL query builders.
+// "<order_column> <order_direction> [NULLS FIRST|LAST]" suitable for SQL
+// query builders.
+//
+// Example: for Orderings: [{"a", "ASC"}, {"b", "DESC", "LAST"}] returns
+// ["a ASC", "b DESC NULLS LAST"].
 //
-// Example: for Orderings: [{"a", "ASC"}, {"b", "DESC"}] returns ["a ASC", "b DESC"].
+// Deprecated: columns are rendered as they are given, so reserved words such
+// as "order" or "t.select" break the query. Use ToDialectSQL or Apply.
 func (o Orderings) ToSQLSlice() []string {
 	ret := make([]string, 0, len(o))
 	for _, ordering := range o {
-		ret = append(ret, fmt.Sprintf("%s %s", ordering.Column, ordering.Direction))
+		ret = append(ret, ordering.render(_questionMarkDialect)...)
 	}
 
 	return ret
@@ -83,25 +254,103 @@ func (o Orderings) ToSQLSlice() []string {
 // Usage:
 //
 //	query := fmt.Sprintf("SELECT * FROM table ORDER BY %s", orderings.ToSQL())
+//
+// Deprecated: columns are rendered as they are given, so reserved words such
+// as "order" or "t.select" break the query. Use ToDialectSQL or Apply.
 func (o Orderings) ToSQL() string {
 	return strings.Join(o.ToSQLSlice(), ", ")
 }
 
-// Apply applies the ordering to a gorm query.
+// ToDialectSQL works like ToSQL, but quotes columns for the dialect. The
+// placement of NULL values is emulated with an extra sort key if the dialect
+// does not support NULLS FIRST|LAST.
+//
+// Example: for [{"a", "ASC"}, {"t.order", "DESC"}] and DialectPostgres
+// returns `"a" ASC, "t"."order" DESC`. For [{"a", "ASC", "LAST"}] and
+// DialectMySQL returns "`a` IS NULL ASC, `a` ASC".
+func (o Orderings) ToDialectSQL(dialect Dialect) string {
+	ret := make([]string, 0, len(o))
+	for _, ordering := range o {
+		ret = append(ret, ordering.render(dialect)...)
+	}
+
+	return strings.Join(ret, ", ")
+}
+
+// render returns the sort keys of the ordering quoted for the dialect.
+func (o OrderBy) render(dialect Dialect) []string {
+	column := o.sqlKey(dialect)
+	ret := fmt.Sprintf("%s %s", o.comparisonKey(dialect), o.Direction)
+
+	switch {
+	case o.Nulls == NullsDBDefault:
+		return []string{ret}
+	case dialect.SupportsNullsOrdering():
+		return []string{fmt.Sprintf("%s NULLS %s", ret, o.Nulls)}
+	case o.Nulls.last(dialect, o.Direction) == NullsDBDefault.last(dialect, o.Direction):
+		// The database places NULL values as requested.
+		return []string{ret}
+	}
+
+	// The sort key is 1 for NULL values and 0 otherwise.
+	keyDirection := DirectionASC
+	if o.Nulls == NullsFirst {
+		keyDirection = DirectionDESC
+	}
+
+	return []string{fmt.Sprintf("%s %s", nullsSortKey(dialect, column), keyDirection), ret}
+}
+
+// Invert returns a copy of Orderings with every direction and placement of
+// NULL values inverted. Used to fetch the dataset backwards.
+//
+// Example: for [{"a", "ASC"}, {"b", "DESC", "LAST"}] returns [{"a", "DESC"}, {"b", "ASC", "FIRST"}].
+func (o Orderings) Invert() Orderings {
+	ret := make(Orderings, 0, len(o))
+	for _, ordering := range o {
+		ordering.Direction = ordering.Direction.Invert()
+		ordering.Nulls = ordering.Nulls.Invert()
+		ret = append(ret, ordering)
+	}
+
+	return ret
+}
+
+// validateDialect checks that the orderings can be rendered for the dialect.
+func (o Orderings) validateDialect(dialect Dialect) error {
+	for _, ordering := range o {
+		err := ordering.validateDialect(dialect)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasDefaultNulls returns true if no ordering sets the placement of NULL values.
+func (o Orderings) hasDefaultNulls() bool {
+	return lo.EveryBy(o, func(item OrderBy) bool {
+		return item.Nulls == NullsDBDefault
+	})
+}
+
+// Apply applies the ordering to a gorm query. Columns are quoted for the
+// dialect of db.
 func (o Orderings) Apply(db *gorm.DB) *gorm.DB {
-	return db.Order(o.ToSQL())
+	return db.Order(o.ToDialectSQL(gormDialectOf(db)))
 }
 
 func (o Orderings) validate() error {
 	if len(o) == 0 {
-		return fmt.Errorf("empty ordering list")
+		return ErrEmptySort
 	}
 
 	var err error
 	for _, ordering := range o {
 		err = ordering.validate()
 		if err != nil {
-			return err
+			return fmt.Errorf("%w: %w", ErrInvalidSort, err)
 		}
 	}
 
@@ -109,34 +358,63 @@ func (o Orderings) validate() error {
 }
 
 // ParseSort builds Orderings from a list of strings in the format
-// "column asc|desc". Column aliases are resolved via ColumnMapping.
-// Returns an error if an alias is not found in the mapping.
-func ParseSort(stringsOrderings []string, columnMapping ColumnMapping) (Orderings, error) {
+// "column asc|desc [nulls first|last]". Column aliases are resolved via
+// ColumnMapping, then via ExprMappings into orderings by expressions aliased
+// with the external alias. Returns an error if an alias is not found in the
+// mappings.
+func ParseSort(stringsOrderings []string, columnMapping ColumnMapping, exprMappings ...ExprMapping) (Orderings, error) {
 	ret := make([]OrderBy, 0, len(stringsOrderings))
 	aliases := lo.Keys(columnMapping)
+	for _, exprMapping := range exprMappings {
+		aliases = append(aliases, lo.Keys(exprMapping)...)
+	}
 
 	for _, stringOrdering := range stringsOrderings {
 		cutStringOrdering := strings.Split(strings.TrimSpace(stringOrdering), " ")
-		if len(cutStringOrdering) != 2 {
-			return nil, fmt.Errorf("invalid ordering string format '%s'", stringOrdering)
+		if len(cutStringOrdering) != 2 && (len(cutStringOrdering) != 4 || !strings.EqualFold(cutStringOrdering[2], "nulls")) {
+			return nil, fmt.Errorf("%w: invalid ordering string format '%s'", ErrInvalidSort, stringOrdering)
 		}
 
 		columnAlias := cutStringOrdering[0]
 		direction := Direction(strings.ToUpper(cutStringOrdering[1]))
-		columnName := columnMapping[columnAlias]
-		if columnName == "" {
-			return nil, fmt.Errorf("invalid column alias. closest: '%s'", closestAlias(columnAlias, aliases))
+		if !direction.Valid() {
+			return nil, fmt.Errorf("%w: invalid ordering direction '%s'", ErrInvalidSort, cutStringOrdering[1])
+		}
+		nulls := NullsDBDefault
+		if len(cutStringOrdering) == 4 {
+			nulls = Nulls(strings.ToUpper(cutStringOrdering[3]))
+			if nulls != NullsFirst && nulls != NullsLast {
+				return nil, fmt.Errorf("%w: invalid ordering nulls placement '%s'", ErrInvalidSort, cutStringOrdering[3])
+			}
+		}
+		ordering, ok := resolveSortAlias(columnAlias, columnMapping, exprMappings)
+		if !ok {
+			return nil, &UnknownSortAliasError{Alias: columnAlias, Suggestion: closestAlias(columnAlias, aliases)}
 		}
 
-		ret = append(ret, OrderBy{
-			Column:    columnName,
-			Direction: direction,
-		})
+		ordering.Direction, ordering.Nulls = direction, nulls
+		ret = append(ret, ordering)
 	}
 
 	return ret, nil
 }
 
+// resolveSortAlias returns the ordering of an external alias without the
+// direction. Column mapping takes precedence over expressions.
+func resolveSortAlias(alias ColumnAlias, columnMapping ColumnMapping, exprMappings []ExprMapping) (OrderBy, bool) {
+	if column := columnMapping[alias]; column != "" {
+		return OrderBy{Column: column}, true
+	}
+
+	for _, exprMapping := range exprMappings {
+		if expr := exprMapping[alias]; expr != "" {
+			return OrderBy{Column: alias, Expr: expr}, true
+		}
+	}
+
+	return OrderBy{}, false
+}
+
 func closestAlias(input ColumnAlias, dataSet []ColumnAlias) ColumnAlias {
 	minDist := math.MaxInt
 	closest := ""
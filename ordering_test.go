@@ -36,6 +36,11 @@ func Test_Orderings_validate(t *testing.T) {
 		{"empty returns error", Orderings{}, false},
 		{"invalid direction", Orderings{{Column: "id", Direction: "bad"}}, false},
 		{"valid list", Orderings{{Column: "id", Direction: DirectionASC}}, true},
+		{"qualified quoted column", Orderings{{Column: `public."user"."order"`, Direction: DirectionASC}}, true},
+		{"unbalanced quote", Orderings{{Column: `"id`, Direction: DirectionASC}}, false},
+		{"injection", Orderings{{Column: "id; DROP TABLE users", Direction: DirectionASC}}, false},
+		{"nulls last", Orderings{{Column: "id", Direction: DirectionASC, Nulls: NullsLast}}, true},
+		{"invalid nulls", Orderings{{Column: "id", Direction: DirectionASC, Nulls: "MIDDLE"}}, false},
 	}
 	for _, tt := range tests {
 		if err := tt.ord.validate(); (err == nil) != tt.ok {
@@ -60,6 +65,11 @@ func Test_ParseSort(t *testing.T) {
 		{"unknown alias", []string{"idx asc"}, false, OrderBy{}},
 		{"valid asc", []string{"id asc"}, true, OrderBy{Column: "t.id", Direction: DirectionASC}},
 		{"valid desc", []string{"name desc"}, true, OrderBy{Column: "t.name", Direction: DirectionDESC}},
+		{"nulls last", []string{"name desc nulls last"}, true, OrderBy{Column: "t.name", Direction: DirectionDESC, Nulls: NullsLast}},
+		{"nulls first", []string{"id ASC NULLS FIRST"}, true, OrderBy{Column: "t.id", Direction: DirectionASC, Nulls: NullsFirst}},
+		{"invalid nulls placement", []string{"id asc nulls middle"}, false, OrderBy{}},
+		{"missing nulls keyword", []string{"id asc first last"}, false, OrderBy{}},
+		{"missing nulls placement", []string{"id asc nulls"}, false, OrderBy{}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -77,6 +87,71 @@ func Test_ParseSort(t *testing.T) {
 	}
 }
 
+func Test_Orderings_Nulls_ToDialectSQL(t *testing.T) {
+	orderings := Orderings{
+		{Column: "a", Direction: DirectionASC, Nulls: NullsLast},
+		{Column: "b", Direction: DirectionDESC, Nulls: NullsLast},
+		{Column: "id", Direction: DirectionASC},
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{DialectPostgres, `"a" ASC NULLS LAST, "b" DESC NULLS LAST, "id" ASC`},
+		{DialectSQLite, `"a" ASC NULLS LAST, "b" DESC NULLS LAST, "id" ASC`},
+		{DialectMySQL, "`a` IS NULL ASC, `a` ASC, `b` DESC, `id` ASC"},
+		{DialectSQLServer, "CASE WHEN [a] IS NULL THEN 1 ELSE 0 END ASC, [a] ASC, [b] DESC, [id] ASC"},
+	}
+	for _, tt := range tests {
+		if got := orderings.ToDialectSQL(tt.dialect); got != tt.expected {
+			t.Errorf("%s: got %s want %s", tt.dialect.Name(), got, tt.expected)
+		}
+	}
+
+	if got := orderings.ToSQL(); got != "a ASC NULLS LAST, b DESC NULLS LAST, id ASC" {
+		t.Errorf("ToSQL: got %s", got)
+	}
+
+	inverted := orderings.Invert()
+	if got := inverted.ToDialectSQL(DialectMySQL); got != "`a` IS NULL DESC, `a` DESC, `b` ASC, `id` DESC" {
+		t.Errorf("inverted: got %s", got)
+	}
+}
+
+func Test_ParseSort_ExprMapping(t *testing.T) {
+	columnMapping := ColumnMapping{"id": "users.id"}
+	exprMapping := ExprMapping{"email": "LOWER(users.email)"}
+
+	got, err := ParseSort([]string{"email asc nulls last", "id desc"}, columnMapping, exprMapping)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Orderings{
+		{Column: "email", Direction: DirectionASC, Expr: "LOWER(users.email)", Nulls: NullsLast},
+		{Column: "users.id", Direction: DirectionDESC},
+	}
+	if len(got) != len(expected) || got[0] != expected[0] || got[1] != expected[1] {
+		t.Fatalf("got %v want %v", got, expected)
+	}
+	if err = got.validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	if got := got.ToDialectSQL(DialectPostgres); got != `(LOWER(users.email)) ASC NULLS LAST, "users"."id" DESC` {
+		t.Errorf("postgres: got %s", got)
+	}
+	if got := got.ToDialectSQL(DialectMySQL); got != "(LOWER(users.email)) IS NULL ASC, (LOWER(users.email)) ASC, `users`.`id` DESC" {
+		t.Errorf("mysql: got %s", got)
+	}
+
+	// User input is matched against the aliases only.
+	if _, err = ParseSort([]string{"LOWER(users.email) asc"}, columnMapping, exprMapping); err == nil {
+		t.Errorf("expected error for an expression passed as alias")
+	}
+}
+
 func Test_closestAlias(t *testing.T) {
 	aliases := []ColumnAlias{"id", "name", "created_at"}
 	tests := []struct {
@@ -96,3 +171,41 @@ func Test_closestAlias(t *testing.T) {
 		})
 	}
 }
+
+func Test_Orderings_Collation(t *testing.T) {
+	orderings := Orderings{
+		{Column: "name", Direction: DirectionASC, Collation: "C"},
+		{Column: "id", Direction: DirectionASC},
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+		ok       bool
+	}{
+		{DialectPostgres, `"name" COLLATE "C" ASC, "id" ASC`, true},
+		{DialectSQLite, `"name" COLLATE C ASC, "id" ASC`, true},
+		{DialectMySQL, "`name` COLLATE C ASC, `id` ASC", true},
+	}
+	for _, tt := range tests {
+		if err := orderings.validateDialect(tt.dialect); (err == nil) != tt.ok {
+			t.Errorf("%s: ok=%v err=%v", tt.dialect.Name(), tt.ok, err)
+		}
+		if got := orderings.ToDialectSQL(tt.dialect); got != tt.expected {
+			t.Errorf("%s: got %s want %s", tt.dialect.Name(), got, tt.expected)
+		}
+	}
+
+	icu := Orderings{{Column: "name", Direction: DirectionASC, Collation: "en-US-x-icu"}}
+	if err := icu.validateDialect(DialectPostgres); err != nil {
+		t.Errorf("postgres: unexpected error %v", err)
+	}
+	if err := icu.validateDialect(DialectSQLServer); err == nil {
+		t.Errorf("sqlserver: expected error")
+	}
+
+	injection := Orderings{{Column: "name", Direction: DirectionASC, Collation: `C" ASC; DROP TABLE users; --`}}
+	if err := injection.validate(); err == nil {
+		t.Errorf("expected error for an invalid collation")
+	}
+}
@@ -0,0 +1,133 @@
+package gopager
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// _requestFingerprintLength is the length of the request fingerprint stored in tokens.
+const _requestFingerprintLength = 16
+
+var (
+	// ErrInvalidPageToken is returned when the page token cannot be decoded.
+	ErrInvalidPageToken = errors.New("invalid page token")
+	// ErrPageTokenRequestMismatch is returned when request parameters other
+	// than the page size changed since the page token was issued.
+	ErrPageTokenRequestMismatch = errors.New("page token does not match the request parameters")
+	// ErrNegativePageSize is returned when the page size is below zero.
+	ErrNegativePageSize = errors.New("page size must not be negative")
+)
+
+// InvalidArgumentError describes an invalid field of a list request. It
+// corresponds to codes.InvalidArgument in gRPC and to 400 Bad Request in HTTP.
+type InvalidArgumentError struct {
+	// Field is the name of the invalid request field, e.g. "page_token".
+	Field string
+	// Err is the cause, e.g. ErrInvalidPageToken, ErrPageTokenRequestMismatch
+	// or ErrNegativePageSize.
+	Err error
+}
+
+func (e *InvalidArgumentError) Error() string {
+	return fmt.Sprintf("invalid argument '%s': %s", e.Field, e.Err)
+}
+
+func (e *InvalidArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// PageTokenRequest is a list request following AIP-158. Request messages
+// generated by protoc-gen-go with page_size and page_token fields implement
+// it as is.
+//
+// If the request also has a filter (GetFilter() string) or an order_by
+// (GetOrderBy() string) field, page tokens are bound to their values.
+type PageTokenRequest interface {
+	GetPageSize() int32
+	GetPageToken() string
+}
+
+// DecodePageTokenRequest converts an AIP-158 list request into
+// *CursorPager[*DefaultCursor]. See TokenCodec.DecodePageTokenRequest.
+func DecodePageTokenRequest(req PageTokenRequest, orderBy ...OrderBy) (*CursorPager[*DefaultCursor], error) {
+	return (*TokenCodec)(nil).DecodePageTokenRequest(req, orderBy...)
+}
+
+// DecodePageTokenRequest converts an AIP-158 list request into
+// *CursorPager[*DefaultCursor]. Zero page size means DefaultLimit, the page
+// size is normalized with NormalizeLimit.
+//
+// Cursors built for the next pages are bound to a fingerprint of the request
+// filter and order_by fields. A page token passed with different values is
+// rejected with ErrPageTokenRequestMismatch. All returned errors are
+// *InvalidArgumentError.
+//
+// IMPORTANT:
+// Plain tokens can be modified by clients. Use a TokenCodec with a signer or
+// a cipher to enforce the binding.
+func (c *TokenCodec) DecodePageTokenRequest(
+	req PageTokenRequest,
+	orderBy ...OrderBy,
+) (*CursorPager[*DefaultCursor], error) {
+	if req.GetPageSize() < 0 {
+		return nil, &InvalidArgumentError{Field: "page_size", Err: ErrNegativePageSize}
+	}
+
+	pager, err := c.DecodeCursorPager(int(req.GetPageSize()), req.GetPageToken(), orderBy...)
+	if err != nil {
+		return nil, &InvalidArgumentError{
+			Field: "page_token",
+			Err:   fmt.Errorf("%w: %w", ErrInvalidPageToken, err),
+		}
+	}
+
+	pager.fingerprint = requestFingerprint(req)
+	if cursor := pager.GetCursor(); cursor != nil && !bytes.Equal(cursor.fingerprint, pager.fingerprint) {
+		return nil, &InvalidArgumentError{Field: "page_token", Err: ErrPageTokenRequestMismatch}
+	}
+
+	return pager, nil
+}
+
+// NextPageToken works like NextPageCursor, but returns the encoded token for
+// the next_page_token response field. The token is empty on the last page.
+func NextPageToken[T any](
+	initialPager *CursorPager[*DefaultCursor],
+	resultSet []T,
+	getters Getters[T],
+) ([]T, string, error) {
+	resultSet, cursor, err := NextPageCursor(initialPager, resultSet, getters)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := cursor.Encode()
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot build next page token: %w", err)
+	}
+
+	return resultSet, token, nil
+}
+
+// requestFingerprint hashes the request parameters a page token is bound to.
+func requestFingerprint(req PageTokenRequest) []byte {
+	var filter, orderBy string
+	if r, ok := req.(interface{ GetFilter() string }); ok {
+		filter = r.GetFilter()
+	}
+	if r, ok := req.(interface{ GetOrderBy() string }); ok {
+		orderBy = r.GetOrderBy()
+	}
+
+	// Values are length prefixed, so that no two pairs produce the same input.
+	h := sha256.New()
+	for _, v := range []string{filter, orderBy} {
+		h.Write(binary.AppendUvarint(nil, uint64(len(v))))
+		h.Write([]byte(v))
+	}
+
+	return h.Sum(nil)[:_requestFingerprintLength]
+}
@@ -0,0 +1,78 @@
+package gopager
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testListRequest struct {
+	PageSize  int32
+	PageToken string
+	Filter    string
+	OrderBy   string
+}
+
+func (r testListRequest) GetPageSize() int32   { return r.PageSize }
+func (r testListRequest) GetPageToken() string { return r.PageToken }
+func (r testListRequest) GetFilter() string    { return r.Filter }
+func (r testListRequest) GetOrderBy() string   { return r.OrderBy }
+
+func Test_DecodePageTokenRequest(t *testing.T) {
+	type row struct{ ID int64 }
+	getters := Getters[row]{"id": func(r row) any { return r.ID }}
+	orderBy := OrderBy{Column: "id", Direction: DirectionASC}
+	codec := NewTokenCodec().WithSigner(newTestSigner(t, "v1", "v1"))
+
+	req := testListRequest{PageSize: 2, Filter: `name = "a"`, OrderBy: "id"}
+	pager, err := codec.DecodePageTokenRequest(req, orderBy)
+	require.NoError(t, err)
+	require.Equal(t, 2, pager.GetLimit())
+	require.Nil(t, pager.GetCursor())
+
+	page, token, err := NextPageToken(pager, []row{{1}, {2}}, getters)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.NotEmpty(t, token)
+
+	t.Run("same parameters, other page size", func(t *testing.T) {
+		next := req
+		next.PageToken, next.PageSize = token, 0
+
+		pager, err := codec.DecodePageTokenRequest(next, orderBy)
+		require.NoError(t, err)
+		require.Equal(t, DefaultLimit, pager.GetLimit())
+		require.Equal(t, []CursorElement{{Column: "id", Value: int64(2), Operator: OperatorGT}}, pager.GetCursor().GetElements())
+	})
+
+	tests := []struct {
+		name      string
+		req       testListRequest
+		wantField string
+		wantErr   error
+	}{
+		{"changed filter", testListRequest{PageToken: token, Filter: `name = "b"`, OrderBy: "id"}, "page_token", ErrPageTokenRequestMismatch},
+		{"changed order by", testListRequest{PageToken: token, Filter: `name = "a"`, OrderBy: "id desc"}, "page_token", ErrPageTokenRequestMismatch},
+		{"negative page size", testListRequest{PageSize: -1, Filter: `name = "a"`, OrderBy: "id"}, "page_size", ErrNegativePageSize},
+		{"malformed token", testListRequest{PageToken: "!", Filter: `name = "a"`, OrderBy: "id"}, "page_token", ErrInvalidPageToken},
+		{"tampered token", testListRequest{PageToken: token + "x", Filter: `name = "a"`, OrderBy: "id"}, "page_token", ErrTokenSignatureMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.DecodePageTokenRequest(tt.req, orderBy)
+			require.ErrorIs(t, err, tt.wantErr)
+
+			var argErr *InvalidArgumentError
+			require.True(t, errors.As(err, &argErr))
+			require.Equal(t, tt.wantField, argErr.Field)
+		})
+	}
+}
+
+func Test_DecodePageTokenRequest_UnboundToken(t *testing.T) {
+	token := NewDefaultCursor(CursorElement{Column: "id", Value: 1, Operator: OperatorGT}).String()
+
+	_, err := DecodePageTokenRequest(testListRequest{PageToken: token}, OrderBy{Column: "id", Direction: DirectionASC})
+	require.ErrorIs(t, err, ErrPageTokenRequestMismatch)
+}
@@ -0,0 +1,238 @@
+package gopager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// SplitPartitions splits the dataset queried by db into up to k ranges of
+// about the same size and returns the token of each range. A range starts
+// from a cursor and stops before the cursor of the next one, see
+// DefaultCursor.WithStopBefore, so ranges never overlap, even when rows are
+// inserted during the scan.
+//
+// Boundaries are computed in a single query with NTILE(k) OVER (ORDER BY ...)
+// in the orderings of the pager, the boundary rows are converted into cursors
+// with getters. The cursor of the pager, if any, is the start of the first
+// range and its stop before bound the end of the last one, so a range can be
+// split again. Fewer ranges are returned if the dataset has less than k rows.
+// Tokens are never empty: PartitionedScan marks finished partitions with "".
+//
+// IMPORTANT:
+//   - The orderings must contain a unique column, see WithUniqueColumns.
+//   - db must select all columns (no Select): the window functions are added
+//     to the selected columns.
+func SplitPartitions[T any](
+	ctx context.Context,
+	db *gorm.DB,
+	initialPager *CursorPager[*DefaultCursor],
+	getters Getters[T],
+	k int,
+) ([]string, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("cannot split partitions: number of partitions must be positive")
+	}
+
+	pager := NewCursorPager[*DefaultCursor]()
+	if initialPager != nil {
+		*pager = *initialPager
+	}
+
+	pager, err := pager.prepare(db)
+	if err == nil && pager.cursor.IsBackward() {
+		err = fmt.Errorf("backward cursor cannot be split")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot split partitions: %w", err)
+	}
+
+	var ends []T
+	if k > 1 {
+		ends, err = partitionEnds[T](db.WithContext(ctx), pager, k)
+		if err != nil {
+			return nil, fmt.Errorf("cannot split partitions: %w", err)
+		}
+	}
+
+	first := DefaultCursor{
+		elements:    pager.cursor.GetElements(),
+		sort:        pager.orderings(),
+		codec:       pager.codec,
+		fingerprint: pager.fingerprint,
+	}
+	first.issuedAt, first.expiresAt = pager.tokenLifetime()
+
+	// The end of the last range is the end of the dataset.
+	starts := []*DefaultCursor{&first}
+	for _, row := range lo.DropRight(ends, 1) {
+		cursor, err := newCursorForRow(pager, row, getters, false)
+		if err != nil {
+			return nil, fmt.Errorf("cannot split partitions: %w", err)
+		}
+
+		starts = append(starts, cursor)
+	}
+
+	ret := make([]string, 0, len(starts))
+	for i, cursor := range starts {
+		cursor.stopBefore = pager.cursor.GetStopBefore()
+		if i+1 < len(starts) {
+			cursor.stopBefore = starts[i+1].elements
+		}
+
+		token, err := cursor.Encode()
+		if err != nil {
+			return nil, fmt.Errorf("cannot split partitions: %w", err)
+		}
+
+		ret = append(ret, token)
+	}
+
+	return ret, nil
+}
+
+// partitionEnds returns the last row of each of k tiles of the dataset.
+func partitionEnds[T any](db *gorm.DB, pager *CursorPager[*DefaultCursor], k int) ([]T, error) {
+	sort := pager.orderings()
+	window := fmt.Sprintf("OVER (ORDER BY %s)", sort.ToDialectSQL(gormDialectOf(db)))
+
+	tiles := pager.cursor.apply(countSession(db), pager.cursorOptions(db, sort)).
+		Select(fmt.Sprintf("*, NTILE(%d) %s AS gopager_tile, ROW_NUMBER() %s AS gopager_row", k, window, window))
+	ends := db.Session(&gorm.Session{NewDB: true}).
+		Table("(?) AS gopager_ends", tiles).
+		Select("MAX(gopager_row)").
+		Group("gopager_tile")
+
+	var ret []T
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Table("(?) AS gopager_tiles", tiles).
+		Where("gopager_row IN (?)", ends).
+		Order("gopager_row").
+		Find(&ret).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// PartitionedScan scans the partitions returned by SplitPartitions
+// concurrently, one goroutine per partition, and passes every page to
+// process with the index of the partition. Pages of a partition are processed
+// in order, process is called concurrently for different partitions.
+//
+// The first error stops all partitions. Returns the tokens of the partitions
+// aligned with partitions: the token of a partition which was not scanned
+// completely or "" for a finished one. Pass them to PartitionedScan to resume
+// the scan: finished partitions are skipped, the others keep their indexes. A
+// partition is resumed from the page following the last processed one, so
+// every page is processed at least once. Returns nil if the scan is complete.
+//
+// Usage:
+//
+//	partitions, err := gopager.SplitPartitions(ctx, db.Model(&User{}), pager, getters, 8)
+//	remaining, err := gopager.PartitionedScan(ctx, db.Model(&User{}), pager, partitions, getters,
+//		func(ctx context.Context, partition int, users []User) error {
+//			...
+//		},
+//	)
+func PartitionedScan[T any](
+	ctx context.Context,
+	db *gorm.DB,
+	initialPager *CursorPager[*DefaultCursor],
+	partitions []string,
+	getters Getters[T],
+	process func(ctx context.Context, partition int, page []T) error,
+) ([]string, error) {
+	// Partitions without a cursor are finished.
+	cursors := make([]*DefaultCursor, len(partitions))
+	for i, token := range partitions {
+		cursor, err := initialPager.GetTokenCodec().DecodeCursor(token)
+		if err != nil {
+			return partitions, fmt.Errorf("cannot scan partition %d: %w", i, err)
+		}
+
+		cursors[i] = cursor
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		remaining = make([]string, len(partitions))
+		done      = make([]bool, len(partitions))
+		failed    error
+		once      sync.Once
+		wg        sync.WaitGroup
+	)
+	fail := func(partition int, err error) {
+		once.Do(func() {
+			failed = fmt.Errorf("cannot scan partition %d: %w", partition, err)
+			cancel()
+		})
+	}
+
+	for i, cursor := range cursors {
+		remaining[i] = partitions[i]
+		if cursor == nil {
+			done[i] = true
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			pager := NewCursorPager[*DefaultCursor]()
+			if initialPager != nil {
+				*pager = *initialPager
+			}
+			pager.cursor = cursor
+
+			for ctx.Err() == nil {
+				page, next, err := fetchPage(ctx, db, pager, getters)
+				if err == nil && len(page) != 0 {
+					err = process(ctx, i, page)
+				}
+				if err != nil {
+					fail(i, err)
+					return
+				}
+
+				if next == nil {
+					done[i] = true
+					return
+				}
+
+				remaining[i], err = (*next).Encode()
+				if err != nil {
+					fail(i, err)
+					return
+				}
+				pager.cursor = *next
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !slices.Contains(done, false) {
+		return nil, failed
+	}
+
+	for i := range remaining {
+		if done[i] {
+			remaining[i] = ""
+		}
+	}
+
+	if failed == nil {
+		failed = fmt.Errorf("cannot scan partitions: %w", ctx.Err())
+	}
+
+	return remaining, failed
+}
@@ -0,0 +1,162 @@
+package gopager
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func Test_DefaultCursor_WithStopBefore(t *testing.T) {
+	start := NewDefaultCursor(CursorElement{Column: "id", Value: int64(10), Operator: OperatorGT})
+	end := NewDefaultCursor(CursorElement{Column: "id", Value: int64(20), Operator: OperatorGT})
+
+	where, args, err := start.WithStopBefore(end).ToDialectSQL(DialectPostgres, 1)
+	require.NoError(t, err)
+	require.Equal(t, `(("id" > $1)) AND (("id" <= $2))`, where)
+	require.Equal(t, []driver.Value{int64(10), int64(20)}, args)
+
+	// A range without start is bounded only.
+	bounded := (&DefaultCursor{}).WithStopBefore(end)
+	require.False(t, bounded.IsEmpty())
+	where, _, err = bounded.ToDialectSQL(DialectMySQL, 1)
+	require.NoError(t, err)
+	require.Equal(t, "((`id` <= ?))", where)
+
+	for _, codec := range []*TokenCodec{NewTokenCodec(), NewTokenCodec().WithCursorCodec(BinaryCursorCodec{})} {
+		decoded, err := codec.DecodeCursor(start.WithStopBefore(end).WithTokenCodec(codec).String())
+		require.NoError(t, err)
+		require.Equal(t, start.GetElements(), decoded.GetElements())
+		require.Equal(t, end.GetElements(), decoded.GetStopBefore())
+	}
+}
+
+func Test_PartitionedScan(t *testing.T) {
+	db := newCountTestDB(t)
+	getters := Getters[testCountUser]{"id": func(u testCountUser) any { return u.ID }}
+	pager := NewCursorPager[*DefaultCursor]().WithLimit(2).
+		WithSort(OrderBy{Column: "id", Direction: DirectionASC})
+	query := db.Model(&testCountUser{}).Session(&gorm.Session{})
+
+	partitions, err := SplitPartitions(context.Background(), query, pager, getters, 3)
+	require.NoError(t, err)
+	require.Len(t, partitions, 3)
+
+	var (
+		mu   sync.Mutex
+		seen = map[int][]int{}
+	)
+	process := func(_ context.Context, partition int, page []testCountUser) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, u := range page {
+			seen[partition] = append(seen[partition], u.ID)
+		}
+
+		return nil
+	}
+
+	remaining, err := PartitionedScan(context.Background(), query, pager, partitions, getters, process)
+	require.NoError(t, err)
+	require.Empty(t, remaining)
+	require.Equal(t, map[int][]int{0: {1, 2, 3, 4}, 1: {5, 6, 7}, 2: {8, 9, 10}}, seen)
+
+	t.Run("more partitions than rows", func(t *testing.T) {
+		partitions, err := SplitPartitions(context.Background(), query.Where("id <= ?", 2), pager, getters, 5)
+		require.NoError(t, err)
+		require.Len(t, partitions, 2)
+	})
+
+	t.Run("split a partition", func(t *testing.T) {
+		cursor, err := DecodeCursor(partitions[1])
+		require.NoError(t, err)
+
+		split, err := SplitPartitions(context.Background(), query, pager.WithCursor(cursor), getters, 2)
+		require.NoError(t, err)
+
+		var ids []int
+		_, err = PartitionedScan(context.Background(), query, pager, split, getters,
+			func(_ context.Context, _ int, page []testCountUser) error {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, u := range page {
+					ids = append(ids, u.ID)
+				}
+
+				return nil
+			},
+		)
+		require.NoError(t, err)
+		slices.Sort(ids)
+		require.Equal(t, []int{5, 6, 7}, ids)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		seen = map[int][]int{}
+		failing := func(ctx context.Context, partition int, page []testCountUser) error {
+			if partition == 1 && page[0].ID == 7 {
+				return errFailed
+			}
+
+			return process(ctx, partition, page)
+		}
+
+		remaining, err := PartitionedScan(context.Background(), query, pager, partitions, getters, failing)
+		require.ErrorIs(t, err, errFailed)
+		require.Len(t, remaining, len(partitions))
+		require.NotEmpty(t, remaining[1])
+
+		// Partition 1 fails, the others may be stopped before they finish.
+		first := seen
+		seen = map[int][]int{}
+		remaining, err = PartitionedScan(context.Background(), query, pager, remaining, getters, process)
+		require.NoError(t, err)
+		require.Nil(t, remaining)
+		require.Equal(t, []int{7}, seen[1])
+
+		// Every partition keeps its index on resume.
+		expected := map[int][]int{0: {1, 2, 3, 4}, 1: {5, 6, 7}, 2: {8, 9, 10}}
+		for partition, ids := range expected {
+			got := slices.Concat(first[partition], seen[partition])
+			slices.Sort(got)
+			require.Equal(t, ids, slices.Compact(got), "partition %d", partition)
+		}
+	})
+
+	t.Run("resume finished partitions", func(t *testing.T) {
+		seen = map[int][]int{}
+		remaining, err := PartitionedScan(context.Background(), query, pager, []string{"", partitions[1], ""}, getters, process)
+		require.NoError(t, err)
+		require.Nil(t, remaining)
+		require.Equal(t, map[int][]int{1: {5, 6, 7}}, seen)
+	})
+
+	t.Run("single partition", func(t *testing.T) {
+		pager := NewCursorPager[*DefaultCursor]().WithLimit(2).
+			WithSort(OrderBy{Column: "id", Direction: DirectionASC})
+		single, err := SplitPartitions(context.Background(), query, pager, getters, 1)
+		require.NoError(t, err)
+		require.Len(t, single, 1)
+		require.NotEmpty(t, single[0])
+
+		seen = map[int][]int{}
+		_, err = PartitionedScan(context.Background(), query, pager, single, getters, process)
+		require.NoError(t, err)
+		require.Equal(t, map[int][]int{0: {1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}, seen)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := SplitPartitions(context.Background(), query, pager, getters, 0)
+		require.Error(t, err)
+
+		remaining, err := PartitionedScan(context.Background(), query, pager, []string{"invalid"}, getters, process)
+		require.ErrorIs(t, err, ErrInvalidToken)
+		require.Equal(t, []string{"invalid"}, remaining)
+	})
+}
@@ -0,0 +1,203 @@
+// Package relay builds Relay-style GraphQL connections on top of gopager.
+//
+// The package converts the first/after/last/before connection arguments into
+// *gopager.CursorPager[*gopager.DefaultCursor] and builds a Connection with a
+// cursor for every edge and pageInfo based on lookahead.
+//
+// See https://relay.dev/graphql/connections.htm for the specification.
+package relay
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/samber/lo"
+
+	"github.com/Alp4ka/gopager"
+)
+
+var (
+	// ErrUnsupportedArguments is returned for combinations of connection
+	// arguments other than first/after and last/before, e.g. after with last.
+	ErrUnsupportedArguments = errors.New("unsupported combination of connection arguments")
+	// ErrNegativeLimit is returned when first or last is below zero.
+	ErrNegativeLimit = errors.New("limit must not be negative")
+)
+
+// Args are the connection arguments of a paginated GraphQL field.
+// Nil values mean the argument was not provided.
+type Args struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// PageInfo describes the fetched page of a connection.
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+// Edge is a single element of a connection with its cursor.
+type Edge[T any] struct {
+	Node   T      `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+// Connection is a page of elements in the Relay connection format.
+type Connection[T any] struct {
+	Edges    []Edge[T] `json:"edges"`
+	PageInfo PageInfo  `json:"pageInfo"`
+}
+
+// NewPager converts connection arguments into a pager. See NewPagerWithCodec.
+func NewPager(args Args, orderBy ...gopager.OrderBy) (*gopager.CursorPager[*gopager.DefaultCursor], error) {
+	return NewPagerWithCodec(nil, args, orderBy...)
+}
+
+// NewPagerWithCodec converts connection arguments into a pager, decoding
+// cursors with the codec. Lookahead is always enabled to fill PageInfo.
+//
+// first/after fetch the elements following the after cursor, last/before
+// fetch the elements preceding the before cursor. The limit is normalized
+// with gopager.NormalizeLimit, zero first or last means an empty page.
+// Other combinations of arguments are rejected with ErrUnsupportedArguments.
+//
+// Errors are returned as *gopager.InvalidArgumentError.
+func NewPagerWithCodec(
+	codec *gopager.TokenCodec,
+	args Args,
+	orderBy ...gopager.OrderBy,
+) (*gopager.CursorPager[*gopager.DefaultCursor], error) {
+	err := validateArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		limit  *int
+		cursor *gopager.DefaultCursor
+	)
+	switch {
+	case args.Last != nil || args.Before != nil:
+		limit = args.Last
+		if args.Before != nil {
+			cursor, err = decodeEdgeCursor(codec, *args.Before)
+			if err != nil {
+				return nil, &gopager.InvalidArgumentError{Field: "before", Err: err}
+			}
+		}
+
+		// Elements preceding the edge are fetched backwards. Without 'before'
+		// the last elements of the dataset are fetched.
+		cursor = cursor.Reversed()
+		if cursor == nil {
+			cursor = gopager.NewDefaultCursor().WithBackward(true)
+		}
+	default:
+		limit = args.First
+		if args.After != nil {
+			cursor, err = decodeEdgeCursor(codec, *args.After)
+			if err != nil {
+				return nil, &gopager.InvalidArgumentError{Field: "after", Err: err}
+			}
+		}
+	}
+
+	ret := gopager.NewCursorPager[*gopager.DefaultCursor]().
+		WithTokenCodec(codec).
+		WithLimit(lo.FromPtr(limit)).
+		WithLookahead().
+		WithCursor(cursor).
+		WithSort(orderBy...)
+	if limit != nil && *limit == 0 {
+		ret = ret.WithZeroLimit()
+	}
+
+	return ret, nil
+}
+
+// validateArgs rejects negative limits and combinations of arguments other
+// than first/after and last/before.
+func validateArgs(args Args) error {
+	switch {
+	case args.First != nil && *args.First < 0:
+		return &gopager.InvalidArgumentError{Field: "first", Err: ErrNegativeLimit}
+	case args.Last != nil && *args.Last < 0:
+		return &gopager.InvalidArgumentError{Field: "last", Err: ErrNegativeLimit}
+	case args.First != nil && args.Last != nil:
+		return &gopager.InvalidArgumentError{Field: "last", Err: ErrUnsupportedArguments}
+	case args.After != nil && args.Before != nil:
+		return &gopager.InvalidArgumentError{Field: "before", Err: ErrUnsupportedArguments}
+	case args.First != nil && args.Before != nil:
+		return &gopager.InvalidArgumentError{Field: "before", Err: ErrUnsupportedArguments}
+	case args.Last != nil && args.After != nil:
+		return &gopager.InvalidArgumentError{Field: "after", Err: ErrUnsupportedArguments}
+	default:
+		return nil
+	}
+}
+
+// BuildConnection builds a connection from the result set fetched with the
+// pager. Every edge gets its own cursor built with getters.
+func BuildConnection[T any](
+	pager *gopager.CursorPager[*gopager.DefaultCursor],
+	resultSet []T,
+	getters gopager.Getters[T],
+) (*Connection[T], error) {
+	hasNextPage := gopager.HasNextPage(pager, resultSet)
+	hasPreviousPage := gopager.HasPrevPage(pager, resultSet)
+
+	// 'last' without 'before' fetches the tail of the dataset.
+	if cursor := pager.GetCursor(); cursor.IsBackward() && cursor.IsEmpty() {
+		hasNextPage = false
+	}
+
+	// Rows are returned in the requested order without the lookahead element.
+	nodes := gopager.TrimResultSet(pager, resultSet)
+
+	ret := &Connection[T]{
+		Edges: make([]Edge[T], 0, len(nodes)),
+		PageInfo: PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: hasPreviousPage,
+		},
+	}
+	for _, node := range nodes {
+		cursor, err := gopager.CursorForRow(pager, node, getters)
+		if err != nil {
+			return nil, fmt.Errorf("cannot build connection: %w", err)
+		}
+
+		token, err := cursor.Encode()
+		if err != nil {
+			return nil, fmt.Errorf("cannot build connection: %w", err)
+		}
+
+		ret.Edges = append(ret.Edges, Edge[T]{Node: node, Cursor: token})
+	}
+
+	if len(ret.Edges) > 0 {
+		ret.PageInfo.StartCursor = &ret.Edges[0].Cursor
+		ret.PageInfo.EndCursor = &ret.Edges[len(ret.Edges)-1].Cursor
+	}
+
+	return ret, nil
+}
+
+// decodeEdgeCursor decodes a cursor produced by BuildConnection.
+func decodeEdgeCursor(codec *gopager.TokenCodec, token string) (*gopager.DefaultCursor, error) {
+	cursor, err := codec.DecodeCursor(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor.IsEmpty() || cursor.IsBackward() {
+		return nil, fmt.Errorf("%w: not an edge cursor", gopager.ErrInvalidToken)
+	}
+
+	return cursor, nil
+}
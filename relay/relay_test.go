@@ -0,0 +1,277 @@
+package relay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Alp4ka/gopager"
+)
+
+type item struct {
+	ID int64
+}
+
+var _getters = gopager.Getters[item]{
+	"id": func(i item) any { return i.ID },
+}
+
+var _orderBy = gopager.OrderBy{Column: "id", Direction: gopager.DirectionASC}
+
+// _codec has a fixed clock, so cursors built for the same row are equal.
+var _codec = gopager.NewTokenCodec().WithClock(func() time.Time {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+})
+
+func items(ids ...int64) []item {
+	ret := make([]item, 0, len(ids))
+	for _, id := range ids {
+		ret = append(ret, item{ID: id})
+	}
+
+	return ret
+}
+
+func nodes[T any](conn *Connection[T]) []T {
+	ret := make([]T, 0, len(conn.Edges))
+	for _, edge := range conn.Edges {
+		ret = append(ret, edge.Node)
+	}
+
+	return ret
+}
+
+func edgeCursor(t *testing.T, id int64) string {
+	pager, err := NewPagerWithCodec(_codec, Args{}, _orderBy)
+	require.NoError(t, err)
+
+	cursor, err := gopager.CursorForRow(pager, item{ID: id}, _getters)
+	require.NoError(t, err)
+
+	token, err := cursor.Encode()
+	require.NoError(t, err)
+
+	return token
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func Test_NewPager(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             Args
+		expectedLimit    int
+		expectedBackward bool
+		expectedElements []gopager.CursorElement
+		wantErr          bool
+	}{
+		{
+			name:          "no arguments",
+			args:          Args{},
+			expectedLimit: gopager.DefaultLimit,
+		},
+		{
+			name:          "first",
+			args:          Args{First: ptr(3)},
+			expectedLimit: 3,
+		},
+		{
+			name:          "first and after",
+			args:          Args{First: ptr(3), After: ptr(edgeCursor(t, 5))},
+			expectedLimit: 3,
+			expectedElements: []gopager.CursorElement{
+				{Column: "id", Value: int64(5), Operator: gopager.OperatorGT},
+			},
+		},
+		{
+			name:             "last",
+			args:             Args{Last: ptr(2)},
+			expectedLimit:    2,
+			expectedBackward: true,
+		},
+		{
+			name:             "last and before",
+			args:             Args{Last: ptr(2), Before: ptr(edgeCursor(t, 5))},
+			expectedLimit:    2,
+			expectedBackward: true,
+			expectedElements: []gopager.CursorElement{
+				{Column: "id", Value: int64(5), Operator: gopager.OperatorLT},
+			},
+		},
+		{
+			name:          "zero first",
+			args:          Args{First: ptr(0), After: ptr(edgeCursor(t, 5))},
+			expectedLimit: 0,
+			expectedElements: []gopager.CursorElement{
+				{Column: "id", Value: int64(5), Operator: gopager.OperatorGT},
+			},
+		},
+		{
+			name:             "zero last",
+			args:             Args{Last: ptr(0)},
+			expectedLimit:    0,
+			expectedBackward: true,
+		},
+		{
+			name:    "first and before",
+			args:    Args{First: ptr(3), Before: ptr(edgeCursor(t, 5))},
+			wantErr: true,
+		},
+		{
+			name:    "last and after",
+			args:    Args{Last: ptr(3), After: ptr(edgeCursor(t, 5))},
+			wantErr: true,
+		},
+		{
+			name:    "first and last",
+			args:    Args{First: ptr(1), Last: ptr(1)},
+			wantErr: true,
+		},
+		{
+			name:    "after and before",
+			args:    Args{After: ptr(edgeCursor(t, 1)), Before: ptr(edgeCursor(t, 5))},
+			wantErr: true,
+		},
+		{
+			name:    "negative first",
+			args:    Args{First: ptr(-1)},
+			wantErr: true,
+		},
+		{
+			name:    "negative last",
+			args:    Args{Last: ptr(-1)},
+			wantErr: true,
+		},
+		{
+			name:    "malformed cursor",
+			args:    Args{After: ptr("???")},
+			wantErr: true,
+		},
+		{
+			name:    "backward cursor",
+			args:    Args{After: ptr(gopager.NewDefaultCursor(gopager.CursorElement{Column: "id", Value: int64(1), Operator: gopager.OperatorLT}).WithBackward(true).String())},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pager, err := NewPager(tt.args, _orderBy)
+			if tt.wantErr {
+				var argErr *gopager.InvalidArgumentError
+				require.ErrorAs(t, err, &argErr)
+				require.Equal(t, gopager.CodeInvalidArgument, gopager.ErrorCode(err))
+				return
+			}
+			require.NoError(t, err)
+
+			require.True(t, pager.IsLookahead())
+			require.Equal(t, tt.expectedLimit, pager.GetLimit())
+			require.Equal(t, tt.expectedBackward, pager.GetCursor().IsBackward())
+			require.Equal(t, tt.expectedElements, pager.GetCursor().GetElements())
+		})
+	}
+}
+
+func Test_BuildConnection(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            Args
+		resultSet       []item
+		expectedNodes   []item
+		hasNextPage     bool
+		hasPreviousPage bool
+	}{
+		{
+			name:          "first page",
+			args:          Args{First: ptr(3)},
+			resultSet:     items(1, 2, 3, 4),
+			expectedNodes: items(1, 2, 3),
+			hasNextPage:   true,
+		},
+		{
+			name:            "last page after cursor",
+			args:            Args{First: ptr(3), After: ptr(edgeCursor(t, 3))},
+			resultSet:       items(4, 5),
+			expectedNodes:   items(4, 5),
+			hasPreviousPage: true,
+		},
+		{
+			name:            "last before cursor",
+			args:            Args{Last: ptr(2), Before: ptr(edgeCursor(t, 4))},
+			resultSet:       items(3, 2, 1),
+			expectedNodes:   items(2, 3),
+			hasNextPage:     true,
+			hasPreviousPage: true,
+		},
+		{
+			name:          "last before cursor reaching the beginning",
+			args:          Args{Last: ptr(3), Before: ptr(edgeCursor(t, 3))},
+			resultSet:     items(2, 1),
+			expectedNodes: items(1, 2),
+			hasNextPage:   true,
+		},
+		{
+			name:            "last",
+			args:            Args{Last: ptr(2)},
+			resultSet:       items(10, 9, 8),
+			expectedNodes:   items(9, 10),
+			hasPreviousPage: true,
+		},
+		{
+			name:          "zero first",
+			args:          Args{First: ptr(0)},
+			resultSet:     items(1),
+			expectedNodes: items(),
+			hasNextPage:   true,
+		},
+		{
+			name:            "zero last",
+			args:            Args{Last: ptr(0)},
+			resultSet:       items(10),
+			expectedNodes:   items(),
+			hasPreviousPage: true,
+		},
+		{
+			name:          "empty",
+			args:          Args{First: ptr(3)},
+			resultSet:     nil,
+			expectedNodes: items(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pager, err := NewPagerWithCodec(_codec, tt.args, _orderBy)
+			require.NoError(t, err)
+
+			conn, err := BuildConnection(pager, tt.resultSet, _getters)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedNodes, nodes(conn))
+			require.Equal(t, tt.hasNextPage, conn.PageInfo.HasNextPage)
+			require.Equal(t, tt.hasPreviousPage, conn.PageInfo.HasPreviousPage)
+
+			if len(conn.Edges) == 0 {
+				require.Nil(t, conn.PageInfo.StartCursor)
+				require.Nil(t, conn.PageInfo.EndCursor)
+				return
+			}
+
+			for _, edge := range conn.Edges {
+				require.Equal(t, edgeCursor(t, edge.Node.ID), edge.Cursor)
+			}
+			require.Equal(t, conn.Edges[0].Cursor, *conn.PageInfo.StartCursor)
+			require.Equal(t, conn.Edges[len(conn.Edges)-1].Cursor, *conn.PageInfo.EndCursor)
+		})
+	}
+}
+
+func Test_BuildConnection_MissingGetter(t *testing.T) {
+	pager, err := NewPager(Args{First: ptr(3)}, _orderBy)
+	require.NoError(t, err)
+
+	_, err = BuildConnection(pager, items(1), gopager.Getters[item]{})
+	require.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package gopager
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrTokenTruncated is returned when an encrypted token is too short to
+	// contain the key version, the nonce and the authentication tag.
+	ErrTokenTruncated = errors.New("encrypted cursor token is truncated")
+	// ErrUnknownEncryptionKey is returned when the token was encrypted with a
+	// key version that is not registered in the TokenCipher.
+	ErrUnknownEncryptionKey = errors.New("cursor token encrypted with unknown key")
+	// ErrTokenDecryption is returned when the token cannot be decrypted: it
+	// was encrypted with another key of the same version or has been tampered with.
+	ErrTokenDecryption = errors.New("failed to decrypt cursor token")
+)
+
+// TokenCipher encrypts cursor tokens with AES-GCM so that clients can see
+// neither the sort values nor the column names stored in a token.
+//
+// Keys are identified by a version byte stored in the token. New tokens are
+// encrypted with the active key, while every registered key can decrypt.
+//
+// Encrypted token layout (before base64url encoding):
+//
+//	<key version: 1 byte><nonce: 12 bytes><ciphertext><tag: 16 bytes>
+type TokenCipher struct {
+	activeVersion byte
+	keys          map[byte]cipher.AEAD
+}
+
+// NewTokenCipher creates a TokenCipher. keys maps key versions to AES keys
+// of 16, 24 or 32 bytes, activeVersion selects the key used to encrypt new tokens.
+func NewTokenCipher(activeVersion byte, keys map[byte][]byte) (*TokenCipher, error) {
+	if _, ok := keys[activeVersion]; !ok {
+		return nil, fmt.Errorf("active encryption key version %d is not registered", activeVersion)
+	}
+
+	c := &TokenCipher{
+		activeVersion: activeVersion,
+		keys:          make(map[byte]cipher.AEAD, len(keys)),
+	}
+	for version, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key version %d: %w", version, err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key version %d: %w", version, err)
+		}
+
+		c.keys[version] = aead
+	}
+
+	return c, nil
+}
+
+// GetActiveVersion returns the version of the key used to encrypt new tokens.
+func (c *TokenCipher) GetActiveVersion() byte {
+	if c == nil {
+		return 0
+	}
+
+	return c.activeVersion
+}
+
+// seal encrypts the plaintext with the active key.
+func (c *TokenCipher) seal(plaintext []byte) ([]byte, error) {
+	aead := c.keys[c.activeVersion]
+
+	header := make([]byte, 1+aead.NonceSize())
+	header[0] = c.activeVersion
+	if _, err := io.ReadFull(rand.Reader, header[1:]); err != nil {
+		return nil, fmt.Errorf("cannot generate nonce: %w", err)
+	}
+
+	return aead.Seal(header, header[1:], plaintext, header[:1]), nil
+}
+
+// open decrypts the data produced by seal.
+func (c *TokenCipher) open(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, ErrTokenTruncated
+	}
+
+	version := data[0]
+	aead, ok := c.keys[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: version %d", ErrUnknownEncryptionKey, version)
+	}
+
+	if len(data) < 1+aead.NonceSize()+aead.Overhead() {
+		return nil, ErrTokenTruncated
+	}
+
+	nonce, ciphertext := data[1:1+aead.NonceSize()], data[1+aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, data[:1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenDecryption, err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,146 @@
+package gopager
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCipher(t *testing.T, activeVersion byte, versions ...byte) *TokenCipher {
+	t.Helper()
+
+	keys := make(map[byte][]byte, len(versions))
+	for _, version := range versions {
+		keys[version] = bytes.Repeat([]byte{version + 1}, 32)
+	}
+
+	c, err := NewTokenCipher(activeVersion, keys)
+	require.NoError(t, err)
+
+	return c
+}
+
+func Test_NewTokenCipher(t *testing.T) {
+	tests := []struct {
+		name          string
+		activeVersion byte
+		keys          map[byte][]byte
+		ok            bool
+	}{
+		{"aes-128", 1, map[byte][]byte{1: make([]byte, 16)}, true},
+		{"aes-256", 1, map[byte][]byte{1: make([]byte, 32)}, true},
+		{"active key is not registered", 2, map[byte][]byte{1: make([]byte, 32)}, false},
+		{"invalid key size", 1, map[byte][]byte{1: make([]byte, 10)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTokenCipher(tt.activeVersion, tt.keys)
+			if (err == nil) != tt.ok {
+				t.Errorf("%s: ok=%v err=%v", tt.name, tt.ok, err)
+			}
+		})
+	}
+}
+
+func Test_TokenCodec_Encrypted_DefaultCursor(t *testing.T) {
+	codec := NewTokenCodec().WithCipher(newTestCipher(t, 1, 1))
+	c := NewDefaultCursor(CursorElement{Column: "email", Value: "john@example.com", Operator: OperatorGT}).
+		WithTokenCodec(codec)
+
+	token := c.String()
+	raw, err := _encoder.DecodeString(token)
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "john@example.com")
+	require.NotContains(t, string(raw), "email")
+
+	decoded, err := codec.DecodeCursor(token)
+	require.NoError(t, err)
+	require.Equal(t, c.GetElements(), decoded.GetElements())
+
+	// Nonce is random, so the same cursor never produces the same token.
+	require.NotEqual(t, token, c.String())
+
+	pager, err := RawCursorPager{Limit: 5, StartToken: token}.DecodeWithCodec(
+		codec,
+		OrderBy{Column: "email", Direction: DirectionASC},
+	)
+	require.NoError(t, err)
+	require.Equal(t, c.GetElements(), pager.GetCursor().GetElements())
+}
+
+func Test_TokenCodec_Encrypted_Errors(t *testing.T) {
+	codec := NewTokenCodec().WithCipher(newTestCipher(t, 1, 1))
+	token := NewPseudoCursor(10).WithTokenCodec(codec).String()
+	raw, err := _encoder.DecodeString(token)
+	require.NoError(t, err)
+
+	tampered := bytes.Clone(raw)
+	tampered[len(tampered)-1] ^= 0xFF
+
+	tests := []struct {
+		name    string
+		codec   *TokenCodec
+		token   string
+		wantErr error
+	}{
+		{
+			name:    "unknown key version",
+			codec:   NewTokenCodec().WithCipher(newTestCipher(t, 2, 2)),
+			token:   token,
+			wantErr: ErrUnknownEncryptionKey,
+		},
+		{
+			name: "wrong key with the same version",
+			codec: NewTokenCodec().WithCipher(func() *TokenCipher {
+				c, err := NewTokenCipher(1, map[byte][]byte{1: make([]byte, 32)})
+				require.NoError(t, err)
+				return c
+			}()),
+			token:   token,
+			wantErr: ErrTokenDecryption,
+		},
+		{
+			name:    "truncated ciphertext",
+			codec:   codec,
+			token:   _encoder.EncodeToString(raw[:20]),
+			wantErr: ErrTokenTruncated,
+		},
+		{
+			name:    "tampered ciphertext",
+			codec:   codec,
+			token:   _encoder.EncodeToString(tampered),
+			wantErr: ErrTokenDecryption,
+		},
+		{
+			name:    "plain token",
+			codec:   codec,
+			token:   NewPseudoCursor(10).String(),
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.codec.DecodePseudoCursor(tt.token)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_TokenCodec_Encrypted_And_Signed(t *testing.T) {
+	codec := NewTokenCodec().
+		WithCipher(newTestCipher(t, 2, 1, 2)).
+		WithSigner(newTestSigner(t, "v1", "v1"))
+	oldCodec := NewTokenCodec().
+		WithCipher(newTestCipher(t, 1, 1)).
+		WithSigner(newTestSigner(t, "v1", "v1"))
+
+	oldToken := NewPseudoCursor(42).WithTokenCodec(oldCodec).String()
+
+	decoded, err := codec.DecodePseudoCursor(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, 42, decoded.GetOffset())
+}
@@ -0,0 +1,360 @@
+package gopager
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidToken is returned when a cursor token cannot be decoded or does
+// not describe a valid cursor. Errors of specific causes, e.g.
+// ErrCursorExpired or ErrTokenSignatureMismatch, are wrapped as well.
+var ErrInvalidToken = errors.New("invalid cursor token")
+
+// TokenCodec defines how cursor tokens are turned into strings and back.
+// A nil *TokenCodec is valid and produces plain base64url encoded tokens.
+//
+// Attach a codec to a pager with CursorPager.WithTokenCodec so that cursors
+// built by NextPageCursor and NextPagePseudoCursor are encoded with it, and use
+// the codec Decode* methods to parse incoming tokens.
+type TokenCodec struct {
+	cursorCodec CursorCodec
+	signer      *TokenSigner
+	cipher      *TokenCipher
+	clock       func() time.Time
+	// exprMappings resolve expression aliases of the orderings stored in
+	// tokens.
+	exprMappings []ExprMapping
+}
+
+func NewTokenCodec() *TokenCodec {
+	return new(TokenCodec)
+}
+
+// WithCursorCodec sets the format of issued tokens. Defaults to JSONCursorCodec.
+//
+// Decoding detects the format of a token automatically: the configured codec
+// and every built-in codec are accepted.
+func (c *TokenCodec) WithCursorCodec(cursorCodec CursorCodec) *TokenCodec {
+	if c == nil {
+		c = new(TokenCodec)
+	}
+
+	c.cursorCodec = cursorCodec
+
+	return c
+}
+
+// GetCursorCodec returns the codec used to serialize issued tokens.
+func (c *TokenCodec) GetCursorCodec() CursorCodec {
+	if c == nil || c.cursorCodec == nil {
+		return JSONCursorCodec{}
+	}
+
+	return c.cursorCodec
+}
+
+// WithSigner enables HMAC signing of issued tokens. Decoding rejects tokens
+// which are not signed or whose signature does not match.
+func (c *TokenCodec) WithSigner(signer *TokenSigner) *TokenCodec {
+	if c == nil {
+		c = new(TokenCodec)
+	}
+
+	c.signer = signer
+
+	return c
+}
+
+// WithCipher enables AES-GCM encryption of issued tokens. Decoding rejects
+// tokens which cannot be decrypted.
+func (c *TokenCodec) WithCipher(cipher *TokenCipher) *TokenCodec {
+	if c == nil {
+		c = new(TokenCodec)
+	}
+
+	c.cipher = cipher
+
+	return c
+}
+
+// GetCipher returns the cipher used by the codec.
+func (c *TokenCodec) GetCipher() *TokenCipher {
+	if c == nil {
+		return nil
+	}
+
+	return c.cipher
+}
+
+// WithClock sets the clock used to stamp issued tokens and to check their
+// expiration. Defaults to time.Now.
+func (c *TokenCodec) WithClock(clock func() time.Time) *TokenCodec {
+	if c == nil {
+		c = new(TokenCodec)
+	}
+
+	c.clock = clock
+
+	return c
+}
+
+// WithExprMappings registers the expressions of the orderings by
+// expressions, see ParseSort. Tokens store only the aliases of expressions:
+// decoding resolves them with the mappings and rejects tokens with unknown
+// aliases, so changing an expression does not invalidate issued tokens.
+func (c *TokenCodec) WithExprMappings(exprMappings ...ExprMapping) *TokenCodec {
+	if c == nil {
+		c = new(TokenCodec)
+	}
+
+	c.exprMappings = exprMappings
+
+	return c
+}
+
+// GetSigner returns the signer used by the codec.
+func (c *TokenCodec) GetSigner() *TokenSigner {
+	if c == nil {
+		return nil
+	}
+
+	return c.signer
+}
+
+// DecodeCursor attempts to parse a token produced by the codec into *DefaultCursor.
+// All returned errors wrap ErrInvalidToken.
+func (c *TokenCodec) DecodeCursor(token string) (*DefaultCursor, error) {
+	if len(token) == 0 {
+		return nil, nil
+	}
+
+	envelope, err := c.decode(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode cursor: %w", ErrInvalidToken, err)
+	}
+
+	cursorCodec, err := c.cursorCodecFor(envelope.format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode cursor: %w", ErrInvalidToken, err)
+	}
+
+	elems, err := cursorCodec.UnmarshalElements(envelope.body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	var stopBefore []CursorElement
+	if len(envelope.stopBefore) != 0 {
+		stopBefore, err = cursorCodec.UnmarshalElements(envelope.stopBefore)
+		if err != nil {
+			return nil, fmt.Errorf("%w: stop before: %w", ErrInvalidToken, err)
+		}
+	}
+
+	var sort Orderings
+	if len(envelope.sort) != 0 {
+		sort, err = unmarshalOrderings(envelope.sort, c.getExprMappings())
+		if err == nil {
+			err = sort.validate()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: sort: %w", ErrInvalidToken, err)
+		}
+	}
+
+	ret := &DefaultCursor{
+		elements:    elems,
+		stopBefore:  stopBefore,
+		sort:        sort,
+		backward:    envelope.backward,
+		codec:       c,
+		issuedAt:    envelope.issuedAt,
+		expiresAt:   envelope.expiresAt,
+		fingerprint: envelope.fingerprint,
+	}
+
+	// Columns and operators are rendered into SQL, never trust them.
+	err = ret.check()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	return ret, nil
+}
+
+// DecodePseudoCursor attempts to parse a token produced by the codec into *PseudoCursor.
+// All returned errors wrap ErrInvalidToken.
+func (c *TokenCodec) DecodePseudoCursor(token string) (*PseudoCursor, error) {
+	if len(token) == 0 {
+		return nil, nil
+	}
+
+	envelope, err := c.decode(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode pseudo cursor: %w", ErrInvalidToken, err)
+	}
+
+	cursorCodec, err := c.cursorCodecFor(envelope.format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode pseudo cursor: %w", ErrInvalidToken, err)
+	}
+
+	offset, err := cursorCodec.UnmarshalOffset(envelope.body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	return &PseudoCursor{
+		offset:    offset,
+		codec:     c,
+		issuedAt:  envelope.issuedAt,
+		expiresAt: envelope.expiresAt,
+	}, nil
+}
+
+// DecodeCursorPager decodes a cursor token into *CursorPager. Cursors built
+// for the next pages are encoded with the same codec.
+//
+// If orderBy is empty, the orderings are restored from the token with
+// DefaultCursor.GetOrderings, but only if the codec signs or encrypts tokens:
+// columns of plain tokens are controlled by clients. Expressions are resolved
+// from the aliases with the mappings registered by WithExprMappings. Tokens of
+// cursors built by hand keep no orderings and cannot be restored. Returns
+// *SortMismatchError if orderBy conflicts with the token.
+func (c *TokenCodec) DecodeCursorPager(
+	limit int,
+	rawStartToken string,
+	orderBy ...OrderBy,
+) (*CursorPager[*DefaultCursor], error) {
+	cursor, err := c.DecodeCursor(rawStartToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orderBy) == 0 && c.authenticates() {
+		if !cursor.isStart() && len(cursor.sort) == 0 {
+			return nil, &SortMismatchError{Expected: cursor.GetOrderings()}
+		}
+		orderBy = cursor.GetOrderings()
+	}
+	err = cursor.checkSortPrefix(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&CursorPager[*DefaultCursor]{
+		cursor: cursor,
+		codec:  c,
+	}).WithSubstitutedSort(orderBy...).WithLimit(limit), nil
+}
+
+// DecodePseudoCursorPager decodes a pseudo-cursor token into *CursorPager.
+// Cursors built for the next pages are encoded with the same codec.
+func (c *TokenCodec) DecodePseudoCursorPager(
+	limit int,
+	rawStartToken string,
+	orderBy ...OrderBy,
+) (*CursorPager[*PseudoCursor], error) {
+	cursor, err := c.DecodePseudoCursor(rawStartToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&CursorPager[*PseudoCursor]{
+		cursor: cursor,
+		codec:  c,
+	}).WithSubstitutedSort(orderBy...).WithLimit(limit), nil
+}
+
+// authenticates returns true if tokens are signed or encrypted, so clients
+// cannot forge them.
+func (c *TokenCodec) authenticates() bool {
+	return c.GetSigner() != nil || c.GetCipher() != nil
+}
+
+// now returns the current time according to the codec clock.
+func (c *TokenCodec) now() time.Time {
+	if c == nil || c.clock == nil {
+		return time.Now()
+	}
+
+	return c.clock()
+}
+
+// cursorCodecFor returns the codec able to parse the given format.
+func (c *TokenCodec) cursorCodecFor(format byte) (CursorCodec, error) {
+	if cursorCodec := c.GetCursorCodec(); cursorCodec.Format() == format {
+		return cursorCodec, nil
+	}
+
+	cursorCodec, ok := _builtinCursorCodecs[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown cursor format %d", format)
+	}
+
+	return cursorCodec, nil
+}
+
+// encode converts an envelope with a serialized cursor into a token string.
+func (c *TokenCodec) encode(envelope tokenEnvelope) (string, error) {
+	var err error
+	payload := envelope.marshal()
+	if c.GetCipher() != nil {
+		payload, err = c.cipher.seal(payload)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	token := _encoder.EncodeToString(payload)
+	if c.GetSigner() != nil {
+		token = c.signer.sign(token)
+	}
+
+	return token, nil
+}
+
+// decode converts a token string back into an envelope with a serialized
+// cursor. Returns ErrCursorExpired if the token has expired.
+func (c *TokenCodec) decode(token string) (tokenEnvelope, error) {
+	var err error
+	if c.GetSigner() != nil {
+		token, err = c.signer.verify(token)
+		if err != nil {
+			return tokenEnvelope{}, err
+		}
+	}
+
+	payload, err := _encoder.DecodeString(token)
+	if err != nil {
+		return tokenEnvelope{}, fmt.Errorf("failed to decode base64 encoded token: %w", err)
+	}
+
+	if c.GetCipher() != nil {
+		payload, err = c.cipher.open(payload)
+		if err != nil {
+			return tokenEnvelope{}, err
+		}
+	}
+
+	envelope, err := unmarshalTokenEnvelope(payload)
+	if err != nil {
+		return tokenEnvelope{}, err
+	}
+
+	if err = envelope.checkExpiration(c.now()); err != nil {
+		return tokenEnvelope{}, err
+	}
+
+	return envelope, nil
+}
+
+// getExprMappings returns the expression mappings of the codec.
+func (c *TokenCodec) getExprMappings() []ExprMapping {
+	if c == nil {
+		return nil
+	}
+
+	return c.exprMappings
+}
@@ -0,0 +1,283 @@
+package gopager
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCursorExpired is returned when a token is decoded after its expiration time.
+var ErrCursorExpired = errors.New("cursor token expired")
+
+// Token layout versions. The version is stored in the first byte of the
+// serialized token. Legacy tokens have no version byte: they are either a
+// JSON array (DefaultCursor) or a decimal number (PseudoCursor).
+const (
+	_tokenLayoutV1 byte = 0x01
+	_tokenLayoutV2 byte = 0x02
+)
+
+// Flags of the token envelope.
+const (
+	_envelopeFlagIssuedAt byte = 1 << iota
+	_envelopeFlagExpiresAt
+	_envelopeFlagBackward
+	_envelopeFlagFingerprint
+	_envelopeFlagStopBefore
+	_envelopeFlagSort
+)
+
+// tokenEnvelope wraps a serialized cursor with token metadata.
+//
+// Layout v2:
+//
+//	<version: 0x02><format: 1 byte><flags: 1 byte>[<issued at: varint>][<expires at: varint>][<fingerprint: uvarint length + bytes>][<stop before: uvarint length + bytes>][<sort: uvarint length + bytes>]<body>
+//
+// Layout v1 (read only, JSON format):
+//
+//	<version: 0x01><flags: 1 byte>[<issued at: varint>][<expires at: varint>]<body>
+//
+// Timestamps are stored as Unix seconds and present only if the
+// corresponding flag is set. Format is the CursorCodec identifier of the body.
+// Backward flag marks previous page tokens. Fingerprint binds the token to
+// the request parameters it was issued for. Stop before is the bound of the
+// cursor serialized in the format of the body. Sort are the orderings the
+// cursor was built for, see marshalOrderings.
+type tokenEnvelope struct {
+	format      byte
+	backward    bool
+	issuedAt    time.Time
+	expiresAt   time.Time
+	fingerprint []byte
+	stopBefore  []byte
+	sort        []byte
+	body        []byte
+}
+
+func (e tokenEnvelope) marshal() []byte {
+	var flags byte
+	if !e.issuedAt.IsZero() {
+		flags |= _envelopeFlagIssuedAt
+	}
+	if !e.expiresAt.IsZero() {
+		flags |= _envelopeFlagExpiresAt
+	}
+	if e.backward {
+		flags |= _envelopeFlagBackward
+	}
+	if len(e.fingerprint) > 0 {
+		flags |= _envelopeFlagFingerprint
+	}
+	if len(e.stopBefore) > 0 {
+		flags |= _envelopeFlagStopBefore
+	}
+	if len(e.sort) > 0 {
+		flags |= _envelopeFlagSort
+	}
+
+	ret := make([]byte, 0, 3+5*binary.MaxVarintLen64+len(e.fingerprint)+len(e.stopBefore)+len(e.sort)+len(e.body))
+	ret = append(ret, _tokenLayoutV2, e.format, flags)
+	if flags&_envelopeFlagIssuedAt != 0 {
+		ret = binary.AppendVarint(ret, e.issuedAt.Unix())
+	}
+	if flags&_envelopeFlagExpiresAt != 0 {
+		ret = binary.AppendVarint(ret, e.expiresAt.Unix())
+	}
+	if flags&_envelopeFlagFingerprint != 0 {
+		ret = binary.AppendUvarint(ret, uint64(len(e.fingerprint)))
+		ret = append(ret, e.fingerprint...)
+	}
+	if flags&_envelopeFlagStopBefore != 0 {
+		ret = binary.AppendUvarint(ret, uint64(len(e.stopBefore)))
+		ret = append(ret, e.stopBefore...)
+	}
+	if flags&_envelopeFlagSort != 0 {
+		ret = binary.AppendUvarint(ret, uint64(len(e.sort)))
+		ret = append(ret, e.sort...)
+	}
+
+	return append(ret, e.body...)
+}
+
+func unmarshalTokenEnvelope(data []byte) (tokenEnvelope, error) {
+	if len(data) == 0 {
+		return tokenEnvelope{}, fmt.Errorf("empty token")
+	}
+
+	var (
+		ret  = tokenEnvelope{format: CursorFormatJSON}
+		rest []byte
+	)
+
+	switch version := data[0]; {
+	case isLegacyTokenLayout(version):
+		ret.body = data
+		return ret, nil
+	case version == _tokenLayoutV1:
+		rest = data[1:]
+	case version == _tokenLayoutV2:
+		if len(data) < 2 {
+			return tokenEnvelope{}, fmt.Errorf("token envelope is truncated")
+		}
+		ret.format = data[1]
+		rest = data[2:]
+	default:
+		return tokenEnvelope{}, fmt.Errorf("unsupported token layout version %d", version)
+	}
+
+	if len(rest) < 1 {
+		return tokenEnvelope{}, fmt.Errorf("token envelope is truncated")
+	}
+
+	flags := rest[0]
+	rest = rest[1:]
+	ret.backward = flags&_envelopeFlagBackward != 0
+
+	fnReadTime := func() (time.Time, error) {
+		sec, n := binary.Varint(rest)
+		if n <= 0 {
+			return time.Time{}, fmt.Errorf("token envelope is truncated")
+		}
+		rest = rest[n:]
+
+		return time.Unix(sec, 0).UTC(), nil
+	}
+
+	var err error
+	if flags&_envelopeFlagIssuedAt != 0 {
+		ret.issuedAt, err = fnReadTime()
+		if err != nil {
+			return tokenEnvelope{}, err
+		}
+	}
+	if flags&_envelopeFlagExpiresAt != 0 {
+		ret.expiresAt, err = fnReadTime()
+		if err != nil {
+			return tokenEnvelope{}, err
+		}
+	}
+
+	fnReadBytes := func() ([]byte, error) {
+		n, read := binary.Uvarint(rest)
+		if read <= 0 || n > uint64(len(rest)-read) {
+			return nil, fmt.Errorf("token envelope is truncated")
+		}
+		value := rest[read : read+int(n)]
+		rest = rest[read+int(n):]
+
+		return value, nil
+	}
+
+	if flags&_envelopeFlagFingerprint != 0 {
+		ret.fingerprint, err = fnReadBytes()
+		if err != nil {
+			return tokenEnvelope{}, err
+		}
+	}
+	if flags&_envelopeFlagStopBefore != 0 {
+		ret.stopBefore, err = fnReadBytes()
+		if err != nil {
+			return tokenEnvelope{}, err
+		}
+	}
+	if flags&_envelopeFlagSort != 0 {
+		ret.sort, err = fnReadBytes()
+		if err != nil {
+			return tokenEnvelope{}, err
+		}
+	}
+	ret.body = rest
+
+	return ret, nil
+}
+
+// isLegacyTokenLayout reports whether the first byte of a serialized token
+// belongs to a token issued before versioned layouts were introduced.
+func isLegacyTokenLayout(first byte) bool {
+	return first == '[' || first == '-' || (first >= '0' && first <= '9')
+}
+
+// checkExpiration returns ErrCursorExpired if the envelope has expired at now.
+func (e tokenEnvelope) checkExpiration(now time.Time) error {
+	if !e.expiresAt.IsZero() && !now.Before(e.expiresAt) {
+		return fmt.Errorf("%w at %s", ErrCursorExpired, e.expiresAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// _orderingFlagExpr marks an ordering by an expression stored in the token.
+const _orderingFlagExpr byte = 1 << 0
+
+// marshalOrderings serializes the orderings stored in the token envelope.
+// Every ordering is a flags byte followed by uvarint length prefixed strings:
+// column, direction, placement of NULL values and collation. Expressions are
+// never stored: only the flag marks that the column is an expression alias.
+// Returns nil for empty orderings.
+func marshalOrderings(orderings Orderings) []byte {
+	var ret []byte
+	for _, o := range orderings {
+		var flags byte
+		if o.Expr != "" {
+			flags |= _orderingFlagExpr
+		}
+
+		ret = append(ret, flags)
+		for _, field := range []string{o.Column, string(o.Direction), string(o.Nulls), o.Collation} {
+			ret = binary.AppendUvarint(ret, uint64(len(field)))
+			ret = append(ret, field...)
+		}
+	}
+
+	return ret
+}
+
+// unmarshalOrderings parses orderings serialized by marshalOrderings.
+// Expressions are resolved from the aliases with exprMappings, an alias
+// missing in the mappings is an error.
+func unmarshalOrderings(data []byte, exprMappings []ExprMapping) (Orderings, error) {
+	fnReadString := func() (string, error) {
+		n, read := binary.Uvarint(data)
+		if read <= 0 || n > uint64(len(data)-read) {
+			return "", fmt.Errorf("orderings are truncated")
+		}
+		value := string(data[read : read+int(n)])
+		data = data[read+int(n):]
+
+		return value, nil
+	}
+
+	var ret Orderings
+	for len(data) != 0 {
+		flags := data[0]
+		data = data[1:]
+
+		var fields [4]string
+		for i := range fields {
+			var err error
+			fields[i], err = fnReadString()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		ordering := OrderBy{
+			Column:    fields[0],
+			Direction: Direction(fields[1]),
+			Nulls:     Nulls(fields[2]),
+			Collation: fields[3],
+		}
+		if flags&_orderingFlagExpr != 0 {
+			resolved, ok := resolveSortAlias(ordering.Column, nil, exprMappings)
+			if !ok {
+				return nil, fmt.Errorf("expression alias '%s' is not registered", ordering.Column)
+			}
+			ordering.Expr = resolved.Expr
+		}
+
+		ret = append(ret, ordering)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,127 @@
+package gopager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tokenEnvelope_marshal_unmarshal(t *testing.T) {
+	issuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		envelope tokenEnvelope
+	}{
+		{"body only", tokenEnvelope{body: []byte(`[1]`)}},
+		{"issued at", tokenEnvelope{issuedAt: issuedAt, body: []byte(`[1]`)}},
+		{"issued and expires at", tokenEnvelope{issuedAt: issuedAt, expiresAt: issuedAt.Add(time.Hour), body: []byte(`42`)}},
+		{"before unix epoch", tokenEnvelope{issuedAt: time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), body: []byte(`42`)}},
+		{"fingerprint", tokenEnvelope{issuedAt: issuedAt, fingerprint: []byte{1, 2, 3}, body: []byte(`[1]`)}},
+		{"sort", tokenEnvelope{stopBefore: []byte(`[2]`), sort: marshalOrderings(Orderings{{Column: "id", Direction: DirectionASC}}), body: []byte(`[1]`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unmarshalTokenEnvelope(tt.envelope.marshal())
+			require.NoError(t, err)
+			require.Equal(t, tt.envelope, got)
+		})
+	}
+}
+
+func Test_unmarshalTokenEnvelope_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"unknown version", []byte{0x7F, 0x00}},
+		{"no flags", []byte{_tokenLayoutV1}},
+		{"truncated issued at", []byte{_tokenLayoutV1, _envelopeFlagIssuedAt}},
+		{"truncated expires at", []byte{_tokenLayoutV1, _envelopeFlagIssuedAt | _envelopeFlagExpiresAt, 0x02}},
+		{"truncated fingerprint", []byte{_tokenLayoutV2, CursorFormatJSON, _envelopeFlagFingerprint, 0x05, 0x01}},
+		{"truncated sort", []byte{_tokenLayoutV2, CursorFormatJSON, _envelopeFlagSort, 0x05, 0x01}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := unmarshalTokenEnvelope(tt.data)
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_marshalOrderings(t *testing.T) {
+	sort := Orderings{
+		{Column: "created_at", Direction: DirectionDESC, Nulls: NullsLast},
+		{Column: "email", Direction: DirectionASC, Expr: "LOWER(email)", Collation: "C"},
+	}
+	mapping := ExprMapping{"email": "LOWER(email)"}
+	data := marshalOrderings(sort)
+	require.NotContains(t, string(data), "LOWER")
+
+	got, err := unmarshalOrderings(data, []ExprMapping{mapping})
+	require.NoError(t, err)
+	require.Equal(t, sort, got)
+	require.Nil(t, marshalOrderings(nil))
+
+	_, err = unmarshalOrderings(data[:len(data)-1], []ExprMapping{mapping})
+	require.Error(t, err)
+
+	// Aliases of expressions must be registered.
+	_, err = unmarshalOrderings(data, nil)
+	require.Error(t, err)
+}
+
+func Test_DecodeCursor_LegacyLayout(t *testing.T) {
+	c, err := DecodeCursor(_encoder.EncodeToString([]byte(`[{"c":"id","v":5,"o":">"}]`)))
+	require.NoError(t, err)
+	require.Equal(t, []CursorElement{{Column: "id", Value: float64(5), Operator: OperatorGT}}, c.GetElements())
+	require.True(t, c.GetIssuedAt().IsZero())
+	require.True(t, c.GetExpiresAt().IsZero())
+}
+
+func Test_CursorPager_WithTokenTTL(t *testing.T) {
+	type item struct{ ID int }
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	codec := NewTokenCodec().
+		WithSigner(newTestSigner(t, "v1", "v1")).
+		WithClock(func() time.Time { return now })
+
+	pager, err := codec.DecodeCursorPager(1, "", OrderBy{Column: "id", Direction: DirectionASC})
+	require.NoError(t, err)
+	pager = pager.WithTokenTTL(time.Hour)
+
+	_, next, err := NextPageCursor(pager, []item{{ID: 1}}, Getters[item]{"id": func(i item) any { return i.ID }})
+	require.NoError(t, err)
+	require.Equal(t, now, next.GetIssuedAt())
+	require.Equal(t, now.Add(time.Hour), next.GetExpiresAt())
+
+	pseudoPager := NewCursorPager[*PseudoCursor]().
+		WithLimit(1).
+		WithSort(OrderBy{Column: "id", Direction: DirectionASC}).
+		WithTokenCodec(codec).
+		WithTokenTTL(time.Minute)
+	_, nextPseudo, err := NextPagePseudoCursor(pseudoPager, []item{{ID: 1}})
+	require.NoError(t, err)
+
+	token, pseudoToken := next.String(), nextPseudo.String()
+
+	now = now.Add(time.Minute - time.Second)
+	_, err = codec.DecodeCursor(token)
+	require.NoError(t, err)
+	decodedPseudo, err := codec.DecodePseudoCursor(pseudoToken)
+	require.NoError(t, err)
+	require.Equal(t, nextPseudo.GetExpiresAt(), decodedPseudo.GetExpiresAt())
+
+	now = now.Add(time.Second)
+	_, err = codec.DecodeCursor(token)
+	require.NoError(t, err)
+	_, err = codec.DecodePseudoCursor(pseudoToken)
+	require.ErrorIs(t, err, ErrCursorExpired)
+
+	now = now.Add(time.Hour)
+	_, err = codec.DecodeCursorPager(1, token, OrderBy{Column: "id", Direction: DirectionASC})
+	require.ErrorIs(t, err, ErrCursorExpired)
+}
@@ -0,0 +1,123 @@
+package gopager
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// MinSigningKeyLength is the minimal accepted length of an HMAC key in bytes.
+const MinSigningKeyLength = 32
+
+// _tokenSignatureSeparator separates the token body, the key ID and the tag.
+// It never appears in base64url encoded data.
+const _tokenSignatureSeparator = "."
+
+var (
+	// ErrTokenNotSigned is returned when a signed token is expected but the
+	// provided token carries no signature.
+	ErrTokenNotSigned = errors.New("cursor token is not signed")
+	// ErrTokenSignatureMismatch is returned when the token signature does not
+	// match its content, i.e. the token has been tampered with.
+	ErrTokenSignatureMismatch = errors.New("cursor token signature mismatch")
+	// ErrUnknownSigningKey is returned when the token was signed with a key
+	// that is not registered in the TokenSigner.
+	ErrUnknownSigningKey = errors.New("cursor token signed with unknown key")
+)
+
+var _availableKeyIDSymbols = append([]rune("_-"), lo.AlphanumericCharset...)
+
+// TokenSigner signs cursor tokens with HMAC-SHA256 and verifies them on decode.
+//
+// Tokens are always signed with the active key. Every registered key is
+// accepted during verification, which allows rotating secrets: register the
+// new key as active and keep the old one until already issued tokens expire.
+//
+// Signed token layout:
+//
+//	<token>.<key id>.<base64url(HMAC-SHA256(key, "<key id>.<token>"))>
+type TokenSigner struct {
+	activeKeyID string
+	keys        map[string][]byte
+}
+
+// NewTokenSigner creates a TokenSigner. keys maps key IDs to secrets,
+// activeKeyID selects the key used to sign new tokens.
+//
+// Key IDs may contain only alphanumeric symbols, '_' and '-'. Every key must
+// be at least MinSigningKeyLength bytes long.
+func NewTokenSigner(activeKeyID string, keys map[string][]byte) (*TokenSigner, error) {
+	if _, ok := keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("active signing key '%s' is not registered", activeKeyID)
+	}
+
+	s := &TokenSigner{
+		activeKeyID: activeKeyID,
+		keys:        make(map[string][]byte, len(keys)),
+	}
+	for keyID, key := range keys {
+		if keyID == "" || !lo.Every(_availableKeyIDSymbols, []rune(keyID)) {
+			return nil, fmt.Errorf("signing key id contains forbidden symbols '%s'", keyID)
+		}
+		if len(key) < MinSigningKeyLength {
+			return nil, fmt.Errorf("signing key '%s' is shorter than %d bytes", keyID, MinSigningKeyLength)
+		}
+
+		s.keys[keyID] = slices.Clone(key)
+	}
+
+	return s, nil
+}
+
+// GetActiveKeyID returns the ID of the key used to sign new tokens.
+func (s *TokenSigner) GetActiveKeyID() string {
+	if s == nil {
+		return ""
+	}
+
+	return s.activeKeyID
+}
+
+// sign appends the key ID and the HMAC tag to the token.
+func (s *TokenSigner) sign(token string) string {
+	tag := s.tag(s.activeKeyID, token)
+
+	return strings.Join([]string{token, s.activeKeyID, _encoder.EncodeToString(tag)}, _tokenSignatureSeparator)
+}
+
+// verify checks the signature of a signed token and returns the token
+// without signature.
+func (s *TokenSigner) verify(signedToken string) (string, error) {
+	parts := strings.Split(signedToken, _tokenSignatureSeparator)
+	if len(parts) != 3 {
+		return "", ErrTokenNotSigned
+	}
+
+	token, keyID, encodedTag := parts[0], parts[1], parts[2]
+	if _, ok := s.keys[keyID]; !ok {
+		return "", fmt.Errorf("%w '%s'", ErrUnknownSigningKey, keyID)
+	}
+
+	tag, err := _encoder.DecodeString(encodedTag)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrTokenSignatureMismatch, err)
+	}
+
+	if !hmac.Equal(tag, s.tag(keyID, token)) {
+		return "", ErrTokenSignatureMismatch
+	}
+
+	return token, nil
+}
+
+func (s *TokenSigner) tag(keyID string, token string) []byte {
+	mac := hmac.New(sha256.New, s.keys[keyID])
+	mac.Write([]byte(keyID + _tokenSignatureSeparator + token))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,122 @@
+package gopager
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(t *testing.T, activeKeyID string, keyIDs ...string) *TokenSigner {
+	t.Helper()
+
+	keys := make(map[string][]byte, len(keyIDs))
+	for _, keyID := range keyIDs {
+		keys[keyID] = bytes.Repeat([]byte(keyID), MinSigningKeyLength)
+	}
+
+	signer, err := NewTokenSigner(activeKeyID, keys)
+	require.NoError(t, err)
+
+	return signer
+}
+
+func Test_NewTokenSigner(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), MinSigningKeyLength)
+
+	tests := []struct {
+		name        string
+		activeKeyID string
+		keys        map[string][]byte
+		ok          bool
+	}{
+		{"ok", "v1", map[string][]byte{"v1": key}, true},
+		{"active key is not registered", "v2", map[string][]byte{"v1": key}, false},
+		{"short key", "v1", map[string][]byte{"v1": key[:MinSigningKeyLength-1]}, false},
+		{"key id with separator", "v.1", map[string][]byte{"v.1": key}, false},
+		{"empty key id", "", map[string][]byte{"": key}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTokenSigner(tt.activeKeyID, tt.keys)
+			if (err == nil) != tt.ok {
+				t.Errorf("%s: ok=%v err=%v", tt.name, tt.ok, err)
+			}
+		})
+	}
+}
+
+func Test_TokenCodec_Signed_DefaultCursor(t *testing.T) {
+	codec := NewTokenCodec().WithSigner(newTestSigner(t, "v1", "v1"))
+	c := NewDefaultCursor(CursorElement{Column: "id", Value: 1, Operator: OperatorGT}).WithTokenCodec(codec)
+
+	token := c.String()
+	require.Equal(t, 3, len(strings.Split(token, ".")))
+
+	decoded, err := codec.DecodeCursor(token)
+	require.NoError(t, err)
+	require.Equal(t, token, decoded.String())
+
+	// Unsigned tokens are rejected.
+	_, err = codec.DecodeCursor(NewDefaultCursor(c.GetElements()...).String())
+	require.ErrorIs(t, err, ErrTokenNotSigned)
+
+	// Tampered payload is rejected.
+	forged := NewDefaultCursor(CursorElement{Column: "password", Value: "a", Operator: OperatorGT}).String()
+	parts := strings.Split(token, ".")
+	_, err = codec.DecodeCursor(strings.Join([]string{forged, parts[1], parts[2]}, "."))
+	require.ErrorIs(t, err, ErrTokenSignatureMismatch)
+
+	// Tampered tag is rejected.
+	_, err = codec.DecodeCursor(strings.Join([]string{parts[0], parts[1], parts[0]}, "."))
+	require.ErrorIs(t, err, ErrTokenSignatureMismatch)
+}
+
+func Test_TokenCodec_Signed_PseudoCursor(t *testing.T) {
+	codec := NewTokenCodec().WithSigner(newTestSigner(t, "v1", "v1"))
+
+	token := NewPseudoCursor(20).WithTokenCodec(codec).String()
+	decoded, err := codec.DecodePseudoCursor(token)
+	require.NoError(t, err)
+	require.Equal(t, 20, decoded.GetOffset())
+
+	parts := strings.Split(token, ".")
+	forged := NewPseudoCursor(1000).String()
+	_, err = codec.DecodePseudoCursor(strings.Join([]string{forged, parts[1], parts[2]}, "."))
+	require.ErrorIs(t, err, ErrTokenSignatureMismatch)
+
+	_, err = codec.DecodePseudoCursor(forged)
+	require.ErrorIs(t, err, ErrTokenNotSigned)
+}
+
+func Test_TokenCodec_Signed_KeyRotation(t *testing.T) {
+	oldCodec := NewTokenCodec().WithSigner(newTestSigner(t, "v1", "v1"))
+	newCodec := NewTokenCodec().WithSigner(newTestSigner(t, "v2", "v1", "v2"))
+	nextCodec := NewTokenCodec().WithSigner(newTestSigner(t, "v2", "v2"))
+
+	oldToken := NewPseudoCursor(5).WithTokenCodec(oldCodec).String()
+
+	decoded, err := newCodec.DecodePseudoCursor(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, 5, decoded.GetOffset())
+	require.Contains(t, decoded.String(), ".v2.")
+
+	_, err = nextCodec.DecodePseudoCursor(oldToken)
+	require.ErrorIs(t, err, ErrUnknownSigningKey)
+}
+
+func Test_NextPageCursor_InheritsTokenCodec(t *testing.T) {
+	type item struct{ ID int }
+
+	codec := NewTokenCodec().WithSigner(newTestSigner(t, "v1", "v1"))
+	pager, err := codec.DecodeCursorPager(1, "", OrderBy{Column: "id", Direction: DirectionASC})
+	require.NoError(t, err)
+
+	_, next, err := NextPageCursor(pager, []item{{ID: 7}}, Getters[item]{"id": func(i item) any { return i.ID }})
+	require.NoError(t, err)
+	require.Same(t, codec, next.GetTokenCodec())
+
+	_, err = codec.DecodeCursor(next.String())
+	require.NoError(t, err)
+}
@@ -0,0 +1,272 @@
+package gopager
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTotalCap is the number of records counted by TotalCapped unless set
+// with CursorPager.WithTotalCap.
+const DefaultTotalCap = 1000
+
+// ErrUnsupportedTotalStrategy is returned when the total strategy cannot be
+// used with the database.
+var ErrUnsupportedTotalStrategy = errors.New("unsupported total strategy")
+
+// TotalStrategy defines how CursorPager.Total counts the dataset.
+type TotalStrategy string
+
+const (
+	// TotalExact runs SELECT COUNT(*) over the dataset.
+	TotalExact TotalStrategy = ""
+	// TotalEstimate reads the row estimate of the query planner. Supported by
+	// PostgreSQL (EXPLAIN (FORMAT JSON)) and MySQL (EXPLAIN).
+	TotalEstimate TotalStrategy = "ESTIMATE"
+	// TotalCapped counts up to the cap records: SELECT COUNT(*) FROM (... LIMIT cap+1).
+	// A larger dataset is reported as cap with TotalAccuracyLowerBound.
+	TotalCapped TotalStrategy = "CAPPED"
+	// TotalNone does not count the dataset.
+	TotalNone TotalStrategy = "NONE"
+)
+
+func (s TotalStrategy) Valid() bool {
+	return s == TotalExact || s == TotalEstimate || s == TotalCapped || s == TotalNone
+}
+
+// TotalAccuracy tells how the total number of records was obtained.
+type TotalAccuracy string
+
+const (
+	// TotalAccuracyExact means the total is the exact number of records.
+	TotalAccuracyExact TotalAccuracy = ""
+	// TotalAccuracyEstimated means the total is the planner estimate, e.g.
+	// shown as "about 1.2M".
+	TotalAccuracyEstimated TotalAccuracy = "ESTIMATED"
+	// TotalAccuracyLowerBound means there are more records than the total,
+	// e.g. shown as "1000+".
+	TotalAccuracyLowerBound TotalAccuracy = "LOWER_BOUND"
+	// TotalAccuracyUnknown means the dataset was not counted.
+	TotalAccuracyUnknown TotalAccuracy = "UNKNOWN"
+)
+
+// WithTotalStrategy sets the strategy used by Total and PaginateWithTotal.
+// TotalExact is used by default.
+func (c *CursorPager[CursorType]) WithTotalStrategy(strategy TotalStrategy) *CursorPager[CursorType] {
+	if c == nil {
+		c = new(CursorPager[CursorType])
+	}
+
+	c.totalStrategy = strategy
+
+	return c
+}
+
+// WithTotalCap enables TotalCapped counting up to limit records.
+func (c *CursorPager[CursorType]) WithTotalCap(limit int) *CursorPager[CursorType] {
+	if c == nil {
+		c = new(CursorPager[CursorType])
+	}
+
+	c.totalStrategy = TotalCapped
+	c.totalCap = limit
+
+	return c
+}
+
+// GetTotalStrategy returns the strategy used by Total.
+func (c *CursorPager[CursorType]) GetTotalStrategy() TotalStrategy {
+	if c == nil {
+		return TotalExact
+	}
+
+	return c.totalStrategy
+}
+
+// GetTotalCap returns the number of records counted by TotalCapped.
+func (c *CursorPager[CursorType]) GetTotalCap() int {
+	if c == nil || c.totalCap <= 0 {
+		return DefaultTotalCap
+	}
+
+	return c.totalCap
+}
+
+// Total returns the number of records in the dataset queried by db according
+// to the total strategy, see WithTotalStrategy. As with Count, db is the base
+// query without pagination applied.
+func (c *CursorPager[CursorType]) Total(db *gorm.DB) (int64, TotalAccuracy, error) {
+	switch strategy := c.GetTotalStrategy(); strategy {
+	case TotalExact:
+		ret, err := c.Count(db)
+		return ret, TotalAccuracyExact, err
+	case TotalEstimate:
+		ret, err := estimateTotal(db)
+		if err != nil {
+			return 0, "", fmt.Errorf("cannot estimate total: %w", err)
+		}
+		return ret, TotalAccuracyEstimated, nil
+	case TotalCapped:
+		return cappedTotal(db, c.GetTotalCap())
+	case TotalNone:
+		return 0, TotalAccuracyUnknown, nil
+	default:
+		return 0, "", fmt.Errorf("%w '%s'", ErrUnsupportedTotalStrategy, strategy)
+	}
+}
+
+// cappedTotal counts up to limit records of the dataset.
+func cappedTotal(db *gorm.DB, limit int) (int64, TotalAccuracy, error) {
+	sub := countSession(db)
+	// Selected columns are kept: they affect DISTINCT and GROUP BY.
+	if len(sub.Statement.Selects) == 0 && !sub.Statement.Distinct {
+		sub = sub.Select("1")
+	}
+
+	var ret int64
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Table("(?) AS capped", sub.Limit(limit+1)).
+		Count(&ret).Error
+	if err != nil {
+		return 0, "", fmt.Errorf("cannot count: %w", err)
+	}
+
+	if ret > int64(limit) {
+		return int64(limit), TotalAccuracyLowerBound, nil
+	}
+
+	return ret, TotalAccuracyExact, nil
+}
+
+// estimateTotal returns the planner estimate of the number of records.
+func estimateTotal(db *gorm.DB) (int64, error) {
+	var name string
+	if db.Dialector != nil {
+		name = db.Dialector.Name()
+	}
+
+	var (
+		explain string
+		parse   func(rows *sql.Rows) (float64, error)
+	)
+	switch name {
+	case DialectPostgres.Name():
+		explain, parse = "EXPLAIN (FORMAT JSON) ", parsePostgresExplain
+	case DialectMySQL.Name():
+		explain, parse = "EXPLAIN ", parseMySQLExplain
+	default:
+		return 0, fmt.Errorf("%w: planner estimate is not supported by %s", ErrUnsupportedTotalStrategy, name)
+	}
+
+	// Render the query without running it.
+	stmt := countSession(db).Session(&gorm.Session{DryRun: true}).Find(&[]map[string]any{})
+	if stmt.Error != nil {
+		return 0, stmt.Error
+	}
+
+	rows, err := db.Statement.ConnPool.QueryContext(
+		stmt.Statement.Context,
+		explain+stmt.Statement.SQL.String(),
+		stmt.Statement.Vars...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	ret, err := parse(rows)
+	if err == nil {
+		err = rows.Err()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(math.Round(ret)), nil
+}
+
+// parsePostgresExplain reads the row estimate of the top plan node.
+func parsePostgresExplain(rows *sql.Rows) (float64, error) {
+	if !rows.Next() {
+		return 0, fmt.Errorf("empty query plan")
+	}
+
+	var plan string
+	err := rows.Scan(&plan)
+	if err != nil {
+		return 0, err
+	}
+
+	var nodes []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	err = json.Unmarshal([]byte(plan), &nodes)
+	if err != nil {
+		return 0, fmt.Errorf("invalid query plan: %w", err)
+	}
+	if len(nodes) == 0 {
+		return 0, fmt.Errorf("empty query plan")
+	}
+
+	return nodes[0].Plan.Rows, nil
+}
+
+// parseMySQLExplain multiplies the rows examined for each table by the
+// percentage of them left by the filter, as MySQL estimates joins.
+func parseMySQLExplain(rows *sql.Rows) (float64, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		ret   float64
+		found bool
+	)
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		err = rows.Scan(dest...)
+		if err != nil {
+			return 0, err
+		}
+
+		estimate, filtered := 0.0, 100.0
+		for i, column := range columns {
+			if !values[i].Valid {
+				continue
+			}
+			switch strings.ToLower(column) {
+			case "rows":
+				estimate, err = strconv.ParseFloat(values[i].String, 64)
+			case "filtered":
+				filtered, err = strconv.ParseFloat(values[i].String, 64)
+			default:
+			}
+			if err != nil {
+				return 0, fmt.Errorf("invalid query plan: %w", err)
+			}
+		}
+
+		if !found {
+			ret, found = 1, true
+		}
+		ret *= estimate * filtered / 100
+	}
+	if !found {
+		return 0, fmt.Errorf("empty query plan")
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,106 @@
+package gopager
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CursorPager_Total(t *testing.T) {
+	db := newCountTestDB(t)
+	query := db.Model(&testCountUser{}).Where("active = ?", true).Order("id")
+
+	tests := []struct {
+		name     string
+		pager    *CursorPager[*DefaultCursor]
+		total    int64
+		accuracy TotalAccuracy
+	}{
+		{"exact", NewCursorPager[*DefaultCursor](), 5, TotalAccuracyExact},
+		{"capped above", NewCursorPager[*DefaultCursor]().WithTotalCap(3), 3, TotalAccuracyLowerBound},
+		{"capped equal", NewCursorPager[*DefaultCursor]().WithTotalCap(5), 5, TotalAccuracyExact},
+		{"capped default", NewCursorPager[*DefaultCursor]().WithTotalStrategy(TotalCapped), 5, TotalAccuracyExact},
+		{"none", NewCursorPager[*DefaultCursor]().WithTotalStrategy(TotalNone), 0, TotalAccuracyUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, accuracy, err := tt.pager.Total(query)
+			require.NoError(t, err)
+			require.Equal(t, tt.total, total)
+			require.Equal(t, tt.accuracy, accuracy)
+		})
+	}
+
+	t.Run("capped distinct", func(t *testing.T) {
+		total, accuracy, err := NewCursorPager[*DefaultCursor]().WithTotalCap(1).
+			Total(db.Model(&testCountUser{}).Distinct("active"))
+		require.NoError(t, err)
+		require.EqualValues(t, 1, total)
+		require.Equal(t, TotalAccuracyLowerBound, accuracy)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, _, err := NewCursorPager[*DefaultCursor]().WithTotalStrategy(TotalEstimate).Total(query)
+		require.ErrorIs(t, err, ErrUnsupportedTotalStrategy)
+		require.Equal(t, CodeInternal, ErrorCode(err))
+
+		_, _, err = NewCursorPager[*DefaultCursor]().WithTotalStrategy("APPROXIMATE").Total(query)
+		require.ErrorIs(t, err, ErrUnsupportedTotalStrategy)
+	})
+
+	t.Run("paginate", func(t *testing.T) {
+		pager := NewCursorPager[*DefaultCursor]().WithLimit(2).WithLookahead().
+			WithSort(OrderBy{Column: "id", Direction: DirectionASC}).WithTotalCap(4)
+		getters := Getters[testCountUser]{"id": func(u testCountUser) any { return u.ID }}
+
+		result, err := PaginateWithTotal(context.Background(), db.Model(&testCountUser{}), pager, getters)
+		require.NoError(t, err)
+		require.Len(t, result.Items, 2)
+		require.EqualValues(t, 4, result.Total)
+		require.Equal(t, TotalAccuracyLowerBound, result.TotalAccuracy)
+	})
+}
+
+func Test_CursorPager_Total_Estimate(t *testing.T) {
+	type user struct {
+		ID     int
+		Active bool
+	}
+
+	t.Run("postgres", func(t *testing.T) {
+		_, db, mock, err := newGORMPostgresMock()
+		require.NoError(t, err)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`EXPLAIN (FORMAT JSON) SELECT * FROM "users" WHERE active = $1`)).
+			WithArgs(true).
+			WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).
+				AddRow(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 1234567.4}}]`))
+
+		total, accuracy, err := NewCursorPager[*DefaultCursor]().WithTotalStrategy(TotalEstimate).
+			Total(db.Model(&user{}).Where("active = ?", true).Order("id").Limit(10))
+		require.NoError(t, err)
+		require.EqualValues(t, 1234567, total)
+		require.Equal(t, TotalAccuracyEstimated, accuracy)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		_, db, mock, err := newGORMMySQLMock()
+		require.NoError(t, err)
+
+		mock.ExpectQuery(regexp.QuoteMeta("EXPLAIN SELECT * FROM `users` WHERE active = ?")).
+			WithArgs(true).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "select_type", "table", "key", "rows", "filtered", "Extra"}).
+				AddRow(1, "SIMPLE", "users", nil, "2000000", "10.00", "Using where"))
+
+		total, accuracy, err := NewCursorPager[*DefaultCursor]().WithTotalStrategy(TotalEstimate).
+			Total(db.Model(&user{}).Where("active = ?", true))
+		require.NoError(t, err)
+		require.EqualValues(t, 200000, total)
+		require.Equal(t, TotalAccuracyEstimated, accuracy)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}